@@ -9,7 +9,7 @@ import (
 )
 
 func main() {
-	application := fyneApp.New()
+	application := fyneApp.NewWithID("io.github.sentinelb51.rgoclient") // An ID keeps preferences across runs
 	application.Settings().SetTheme(appTheme.NewNoScrollTheme(theme.DefaultTheme()))
 
 	chatApp := app.NewChatApp(application)
@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
 	"github.com/sentinelb51/revoltgo"
 
 	"RGOClient/internal/cache"
@@ -17,14 +21,19 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
-// Default message cache size per channel.
+// Default cache sizes.
 const (
-	name                     = "Revoltgo Client"
-	iconName                 = "rgo.png"
-	defaultMessageCacheSize  = 500
-	defaultChannelCacheLimit = 5
+	name                      = "Revoltgo Client"
+	iconName                  = "rgo.png"
+	defaultMessageBudget      = 2500 // Messages kept in memory across all channels
+	defaultReferenceCacheSize = 500
+	defaultProfileCacheTTL    = 10 * time.Minute
+	defaultMessageRetention   = 1000 // Messages kept on disk per channel
 )
 
+// messageRetentionPreference is the preference key overriding defaultMessageRetention.
+const messageRetentionPreference = "messageRetention"
+
 // ChatApp encapsulates the state and UI components of the application.
 type ChatApp struct {
 	fyneApp fyne.App
@@ -38,27 +47,86 @@ type ChatApp struct {
 	CurrentServerID  string
 	CurrentChannelID string
 
+	// DM and group channel IDs, most recently active first
+	PrivateChannelIDs []string
+
+	// Private channels missing from the session state (opened through the API): channelID → channel
+	privateChannels map[string]*revoltgo.Channel
+
+	// Relationship overrides from gateway events and API responses: userID → relationship.
+	// Seeded from the Ready payload; the state does not apply relationship updates.
+	relationships map[string]revoltgo.UserRelationshipType
+
+	// Users with a relationship who are missing from the session state (new requests): userID → user
+	relationshipUsers map[string]*revoltgo.User
+
+	// Custom emoji IDs from all servers; details are in the session state
+	EmojiIDs []string
+
 	// Message cache for fast channel switching
 	Messages *cache.MessageCache
 
+	// Individually fetched messages (reply targets, jumped-to history windows)
+	References *cache.ReferenceCache
+
+	// User profiles (bio, banner, mutual servers), fetched when a profile card is opened
+	Profiles *cache.ProfileCache
+
 	// Category collapsed state: "serverID:categoryID" → collapsed
 	collapsedCategories map[string]bool
 
 	// Unread state: channelID → true if unread
 	UnreadChannels map[string]bool
 
+	// Typing state: channelID → userID → expiry timer
+	typingUsers map[string]map[string]*time.Timer
+
+	// Member list state
+	memberListVisible  bool
+	membersFetched     map[string]bool // serverID → full member list requested
+	memberRefreshTimer *time.Timer
+	memberEntries      []memberEntry // Members of the current server as last read from state, for filtering
+
 	// Pending token to save after Ready event
 	pendingSessionToken string
 
+	// Unsent messages of the account, and the channel that stops their sender
+	outbox     *Outbox
+	stopOutbox chan struct{}
+
+	// Optimistically shown messages
+	sentEntries     []OutboxEntry                      // Delivered, shown until the gateway echoes them
+	pendingMessages map[string]*widgets.PendingMessage // Nonce → widget shown in outboxContainer
+	uploadProgress  map[string]float64                 // Nonce → uploaded fraction of the entry being sent
+	uploadLimits    map[string]int64                   // Autumn tag → maximum file size, once fetched
+
+	// Connection state
+	sessionReady    bool          // A Ready event arrived on this session; later ones follow a reconnect
+	reconnecting    bool          // The websocket dropped and is being reopened
+	connectionMutex sync.Mutex    // Guards stopConnection
+	stopConnection  chan struct{} // Closed to stop supervising the session's websocket
+
 	// UI containers
 	serverListContainer  *fyne.Container
 	channelListContainer *fyne.Container
 	messageListContainer *fyne.Container
 	messageScroll        *widgets.ObservableScroll
+	imageScope           *cache.ImageScope // Image loads of the shown messages; cancelled when they are replaced
+	visibleMessageIDs    []string          // Messages last reported to imageScope as on screen
 	messageInput         *input.MessageInput
+	typingIndicator      *widgets.TypingIndicator
+	jumpToPresentBar     *fyne.Container
+	memberList           *widgets.MemberList
+	memberListPanel      *fyne.Container
+	chatView             fyne.CanvasObject // Header, messages and input; hidden while the friends panel is shown
+	friendsPanel         *widgets.FriendsPanel
+	reconnectBanner      *fyne.Container
+	outboxContainer      *fyne.Container // Pending messages of the current channel, below its messages
 
 	// Flags
 	isLoadingHistory bool
+	viewingHistory   bool // Showing a window around a jumped-to message instead of live messages
+	friendsVisible   bool // Friends panel shown in place of the message area
 
 	// UI labels
 	channelHeaderLabel *widget.Label
@@ -77,9 +145,19 @@ func NewChatApp(fyneApp fyne.App) *ChatApp {
 		serverListContainer:  container.NewGridWrap(fyne.NewSize(theme.Sizes.ServerSidebarWidth, theme.Sizes.ServerItemHeight)),
 		channelListContainer: container.NewVBox(),
 		ServerIDs:            make([]string, 0),
-		Messages:             cache.NewMessageCache(defaultMessageCacheSize, defaultChannelCacheLimit),
+		privateChannels:      make(map[string]*revoltgo.Channel),
+		relationships:        make(map[string]revoltgo.UserRelationshipType),
+		relationshipUsers:    make(map[string]*revoltgo.User),
+		Messages:             cache.NewMessageCache(defaultMessageBudget),
+		References:           cache.NewReferenceCache(defaultReferenceCacheSize),
+		Profiles:             cache.NewProfileCache(defaultProfileCacheTTL),
 		collapsedCategories:  make(map[string]bool),
 		UnreadChannels:       make(map[string]bool),
+		typingUsers:          make(map[string]map[string]*time.Timer),
+		memberListVisible:    true,
+		membersFetched:       make(map[string]bool),
+		pendingMessages:      make(map[string]*widgets.PendingMessage),
+		uploadProgress:       make(map[string]float64),
 	}
 
 	app.SetIcon()
@@ -116,19 +194,64 @@ func (app *ChatApp) CurrentServer() *revoltgo.Server {
 	return app.Session.State.Server(app.CurrentServerID)
 }
 
+// channelDisplayName returns a channel's name; direct messages are named after the other user.
+func (app *ChatApp) channelDisplayName(channel *revoltgo.Channel) string {
+	if channel.ChannelType != revoltgo.ChannelTypeDM {
+		return channel.Name
+	}
+
+	if user := app.recipient(channel); user != nil {
+		return user.Username
+	}
+	return "Direct message"
+}
+
 // CurrentChannel returns the current channel, or nil if not set.
 func (app *ChatApp) CurrentChannel() *revoltgo.Channel {
 	// todo: what if we return a dummy channel with fake messages: "You're in a loading screen"
 	if app.Session == nil || app.CurrentChannelID == "" {
 		return nil
 	}
-	return app.Session.State.Channel(app.CurrentChannelID)
+	return app.channel(app.CurrentChannelID)
 }
 
-// OnAvatarTapped handles avatar tap events to implement MessageActions.
-func (app *ChatApp) OnAvatarTapped(userID string) {
-	fmt.Printf("Avatar tapped: %s\n", userID)
-	// TODO: open user profile
+// OnChannelTapped navigates to a channel linked from a message, switching servers if needed.
+func (app *ChatApp) OnChannelTapped(channelID string) {
+	if app.Session == nil {
+		return
+	}
+
+	channel := app.channel(channelID)
+	if channel == nil {
+		return
+	}
+
+	if channel.Server == nil {
+		if app.CurrentServerID != "" {
+			app.showHome()
+			app.SelectChannel(channelID)
+			app.RefreshChannelList()
+			return
+		}
+		app.SelectChannel(channelID)
+		return
+	}
+
+	if *channel.Server != app.CurrentServerID {
+		server := app.Session.State.Server(*channel.Server)
+		if server == nil {
+			return
+		}
+
+		app.CurrentServerID = server.ID
+		app.updateServerSelectionUI(server.ID)
+		app.updateServerHeader(server.Name)
+		app.SelectChannel(channelID)
+		app.RefreshChannelList()
+		return
+	}
+
+	app.SelectChannel(channelID)
 }
 
 // OnImageTapped handles image tap events to implement MessageActions.
@@ -146,26 +269,109 @@ func (app *ChatApp) OnReply(message *revoltgo.Message) {
 	app.window.Canvas().Focus(app.messageInput)
 }
 
-// ResolveMessage resolves a message from cache.
-func (app *ChatApp) ResolveMessage(channelID, messageID string) *revoltgo.Message {
-	// Check cache
-	messages := app.Messages.Get(channelID)
-	for _, m := range messages {
+// CachedMessage returns a message from the channel's cache or the reference cache, or nil.
+// Implements interfaces.MessageActions.
+func (app *ChatApp) CachedMessage(channelID, messageID string) *revoltgo.Message {
+	for _, m := range app.Messages.Get(channelID) {
 		if m.ID == messageID {
 			return m
 		}
 	}
+	return app.References.Get(messageID)
+}
+
+// ResolveMessage returns a cached message, or fetches it in the background and returns nil.
+// For reply previews, which re-render once it arrives; other lookups use CachedMessage.
+func (app *ChatApp) ResolveMessage(channelID, messageID string) *revoltgo.Message {
+	if m := app.CachedMessage(channelID, messageID); m != nil {
+		return m
+	}
+
+	if !app.Messages.IsDeleted(messageID) {
+		app.fetchReference(channelID, messageID)
+	}
 	return nil
 }
 
-// OnDelete handles delete action.
+// IsMessageDeleted reports whether a message was deleted while cached.
+func (app *ChatApp) IsMessageDeleted(messageID string) bool {
+	return app.Messages.IsDeleted(messageID)
+}
+
+// OnDelete asks for confirmation and deletes a message.
+// Holding shift while clicking skips the confirmation.
 func (app *ChatApp) OnDelete(messageID string) {
-	fmt.Printf("Delete message: %s\n", messageID)
+	if app.CurrentChannelID == "" || app.Session == nil {
+		return
+	}
+
+	channelID := app.CurrentChannelID
+	message := app.CachedMessage(channelID, messageID)
+	if message == nil {
+		return
+	}
+
+	if isShiftHeld() {
+		app.deleteMessage(channelID, messageID)
+		return
+	}
+
+	content := container.NewVBox(
+		widget.NewLabel("Are you sure you want to delete this message?"),
+		widgets.NewMessagePreview(message),
+	)
+
+	confirm := dialog.NewCustomConfirm("Delete message", "Delete", "Cancel", content, func(ok bool) {
+		if ok {
+			app.deleteMessage(channelID, messageID)
+		}
+	}, app.window)
+	confirm.Resize(fyne.NewSize(theme.Sizes.DeleteDialogWidth, 0))
+	confirm.Show()
+}
+
+// isShiftHeld returns true if a shift key is currently pressed (desktop only).
+func isShiftHeld() bool {
+	if driver, ok := fyne.CurrentApp().Driver().(desktop.Driver); ok {
+		return driver.CurrentKeyModifiers()&fyne.KeyModifierShift != 0
+	}
+	return false
 }
 
-// OnEdit handles edit action.
+// OnEdit loads a message into the input for editing.
 func (app *ChatApp) OnEdit(messageID string) {
-	fmt.Printf("Edit message: %s\n", messageID)
+	if app.CurrentChannelID == "" || app.messageInput == nil {
+		return
+	}
+
+	message := app.CachedMessage(app.CurrentChannelID, messageID)
+	if message == nil || !app.isOwnMessage(message) {
+		return
+	}
+
+	app.messageInput.StartEdit(message)
+	app.window.Canvas().Focus(app.messageInput)
+}
+
+// editLastMessage starts editing our most recent message in the current channel.
+func (app *ChatApp) editLastMessage() {
+	messages := app.Messages.Get(app.CurrentChannelID)
+	for i := len(messages) - 1; i >= 0; i-- {
+		if app.isOwnMessage(messages[i]) {
+			app.OnEdit(messages[i].ID)
+			return
+		}
+	}
+}
+
+// isOwnMessage returns true if the message was authored by the current user.
+func (app *ChatApp) isOwnMessage(message *revoltgo.Message) bool {
+	if app.Session == nil || message.System != nil || message.Webhook != nil {
+		return false
+	}
+
+	self := app.Session.State.Self()
+	return self != nil && message.Author == self.ID
 }
 
 // Run starts the application main loop.
@@ -179,7 +385,10 @@ func (app *ChatApp) SwitchToMainUI() {
 	app.window.SetContent(app.buildUI())
 	app.window.Resize(fyne.NewSize(theme.Sizes.WindowDefaultWidth, theme.Sizes.WindowDefaultHeight))
 	app.window.SetOnClosed(func() {
+		app.endConnection()
+		app.closeOutbox()
 		cache.GetImageCache().Shutdown()
+		app.Messages.CloseStore()
 		if app.Session != nil {
 			_ = app.Session.Close()
 		}
@@ -211,6 +420,7 @@ func (app *ChatApp) SelectServer(serverID string) {
 
 	app.updateServerSelectionUI(serverID)
 	app.updateServerHeader(server.Name)
+	app.setFriendsVisible(false)
 
 	if len(server.Channels) > 0 {
 		app.SelectChannel(server.Channels[0])
@@ -219,6 +429,7 @@ func (app *ChatApp) SelectServer(serverID string) {
 	}
 
 	app.RefreshChannelList()
+	app.syncMemberListPanel()
 }
 
 // SelectChannel handles channel selection and updates the UI.
@@ -230,9 +441,24 @@ func (app *ChatApp) SelectChannel(channelID string) {
 
 	_, unread := app.UnreadChannels[channelID]
 
+	// Notify the previous channel before switching
+	if app.messageInput != nil {
+		app.messageInput.StopTyping()
+	}
+
 	app.CurrentChannelID = channelID
+	app.Messages.SetCurrent(channelID)
+	app.replaceImageScope(nil)
+	app.setViewingHistory(false)
+	app.refreshOutboxUI()
+	app.setFriendsVisible(false)
+	if app.messageInput != nil {
+		app.messageInput.CancelEdit()
+	}
+	app.refreshTypingIndicator()
+
 	if ch := app.CurrentChannel(); ch != nil {
-		app.updateChannelHeader(ch.Name)
+		app.updateChannelHeader(app.channelDisplayName(ch))
 
 		// Acknowledge last message to clear unreads
 		if unread && ch.LastMessageID != nil {
@@ -246,19 +472,37 @@ func (app *ChatApp) SelectChannel(channelID string) {
 	// Update list visual state (selection + unread)
 	app.syncChannelListUI()
 
-	// Display cached messages immediately if available
-	if cached := app.Messages.Get(channelID); len(cached) > 0 {
+	// Display up-to-date cached messages immediately if available
+	cached := app.Messages.Latest(channelID)
+	if len(cached) > 0 && app.Messages.IsLive(channelID) {
 		app.displayMessages(cached)
 		return
 	}
 
-	app.showLoadingMessages()
-	app.loadChannelMessages(channelID)
+	// Otherwise show cached history, or stored history once read from disk, while the latest messages load
+	if len(cached) > 0 {
+		app.displayMessages(cached)
+	} else {
+		app.showLoadingMessages()
+	}
+	app.loadChannelMessages(channelID, len(cached) == 0)
+}
+
+// messageRetention returns how many messages are kept on disk per channel.
+func (app *ChatApp) messageRetention() int {
+	retention := app.fyneApp.Preferences().IntWithFallback(messageRetentionPreference, defaultMessageRetention)
+	if retention <= 0 {
+		return defaultMessageRetention
+	}
+	return retention
 }
 
 // clearChannelSelection clears the current channel and updates the UI.
 func (app *ChatApp) clearChannelSelection() {
 	app.CurrentChannelID = ""
+	app.Messages.SetCurrent("")
+	app.replaceImageScope(nil)
+	app.refreshOutboxUI()
 	app.refreshMessageList()
 	app.updateChannelHeader("")
 	app.syncChannelListUI()
@@ -0,0 +1,235 @@
+package app
+
+import (
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"github.com/sentinelb51/revoltgo"
+
+	"RGOClient/internal/ui/theme"
+)
+
+// Gateway reconnect timing.
+const (
+	connectionCheckInterval = 2 * time.Second
+	reconnectBaseDelay      = time.Second
+	reconnectMaxDelay       = time.Minute
+)
+
+// Backfill paging after a reconnect.
+const (
+	backfillPageSize = 100
+	backfillMaxPages = 5 // Further behind, the latest page is fetched instead, leaving a gap
+)
+
+// startConnection supervises the websocket of a newly opened session and reconnects it when it drops.
+// revoltgo's own reconnect retries at a fixed interval forever, so it is turned off.
+func (app *ChatApp) startConnection(session *revoltgo.Session) {
+	if session.WS != nil {
+		session.WS.ShouldReconnect = false
+	}
+
+	stop := make(chan struct{})
+
+	app.connectionMutex.Lock()
+	if app.stopConnection != nil {
+		close(app.stopConnection)
+	}
+	app.stopConnection = stop
+	app.connectionMutex.Unlock()
+
+	go app.superviseConnection(session, stop)
+}
+
+// endConnection stops supervising the session's websocket. Later calls do nothing.
+func (app *ChatApp) endConnection() {
+	app.connectionMutex.Lock()
+	defer app.connectionMutex.Unlock()
+
+	if app.stopConnection != nil {
+		close(app.stopConnection)
+		app.stopConnection = nil
+	}
+}
+
+// superviseConnection checks the websocket periodically and reconnects it once it is gone.
+func (app *ChatApp) superviseConnection(session *revoltgo.Session, stop <-chan struct{}) {
+	ticker := time.NewTicker(connectionCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		if !session.IsConnected() {
+			app.reconnect(session, stop)
+		}
+	}
+}
+
+// reconnect opens a new websocket with exponential backoff until it connects or the session ends.
+// The Ready event of the new connection finishes the reconnect (see onReconnected).
+func (app *ChatApp) reconnect(session *revoltgo.Session, stop <-chan struct{}) {
+	// Events are missed from now on, so no cached channel is up to date
+	app.Messages.MarkStale()
+	app.GoDo(func() {
+		app.setReconnecting(true)
+	}, false)
+
+	// Stop the dropped websocket's heartbeat and retries; every attempt opens a new one
+	_ = session.Close()
+
+	delay := reconnectBaseDelay
+	for attempt := 1; ; attempt++ {
+		err := session.Open()
+		if err == nil && session.IsConnected() {
+			session.WS.ShouldReconnect = false
+			return
+		}
+
+		_ = session.Close()
+		log.Printf("Reconnect attempt %d failed (retrying in %s): %v\n", attempt, delay, err)
+
+		// Jitter keeps clients that dropped together from retrying together
+		wait := delay/2 + rand.N(delay/2+1)
+		select {
+		case <-stop:
+			return
+		case <-time.After(wait):
+		}
+		delay = min(delay*2, reconnectMaxDelay)
+	}
+}
+
+// setReconnecting shows or hides the "Reconnecting…" banner.
+func (app *ChatApp) setReconnecting(reconnecting bool) {
+	app.reconnecting = reconnecting
+
+	if app.reconnectBanner == nil {
+		return
+	}
+
+	if reconnecting {
+		app.reconnectBanner.Show()
+	} else {
+		app.reconnectBanner.Hide()
+	}
+}
+
+// buildReconnectBanner creates the banner shown across the top of the window while reconnecting.
+func (app *ChatApp) buildReconnectBanner() *fyne.Container {
+	bg := canvas.NewRectangle(theme.Colors.ReconnectBannerBg)
+
+	label := canvas.NewText("Reconnecting…", theme.Colors.ReconnectBannerText)
+	label.TextSize = 12
+	label.TextStyle = fyne.TextStyle{Bold: true}
+
+	banner := container.NewStack(bg, container.NewPadded(container.NewCenter(label)))
+	if !app.reconnecting {
+		banner.Hide()
+	}
+	return banner
+}
+
+// onReconnected applies the Ready payload of a new connection to the running UI, keeping the
+// current selection, then fetches the messages cached channels missed while disconnected.
+func (app *ChatApp) onReconnected(event *revoltgo.EventReady) {
+	app.applyReady(event)
+	app.setReconnecting(false)
+
+	// The viewed channel stays read
+	if _, unread := app.UnreadChannels[app.CurrentChannelID]; unread {
+		if ch := app.CurrentChannel(); ch != nil && ch.LastMessageID != nil {
+			delete(app.UnreadChannels, app.CurrentChannelID)
+			channelID, lastID := app.CurrentChannelID, *ch.LastMessageID
+			go func() {
+				_ = app.Session.MessageAck(channelID, lastID)
+			}()
+		}
+	}
+
+	app.RefreshServerList()
+	switch {
+	case app.CurrentServerID != "" && app.CurrentServer() == nil:
+		app.selectInitial() // Left or removed from the server while disconnected
+	case app.CurrentServerID == "":
+		app.refreshPrivateChannelList()
+	default:
+		app.RefreshChannelList()
+		app.syncMemberListPanel()
+	}
+	app.refreshFriends()
+
+	// Sends failing while offline are retried now instead of after their backoff
+	if app.outbox != nil {
+		app.outbox.Wake()
+	}
+
+	go app.backfillMessages(app.Session, app.CurrentChannelID)
+}
+
+// backfillMessages fetches the messages every cached channel missed, starting with the current one.
+func (app *ChatApp) backfillMessages(session *revoltgo.Session, currentID string) {
+	newest := app.Messages.NewestIDs()
+
+	if lastID, ok := newest[currentID]; ok {
+		delete(newest, currentID)
+		if app.backfillChannel(session, currentID, lastID) {
+			app.GoDo(func() {
+				if app.CurrentChannelID == currentID && !app.viewingHistory {
+					app.displayMessages(app.Messages.Latest(currentID))
+				}
+			}, false)
+		}
+	}
+
+	for channelID, lastID := range newest {
+		app.backfillChannel(session, channelID, lastID)
+	}
+}
+
+// backfillChannel fetches a channel's messages newer than afterID, page by page.
+// A channel too far behind gets its latest page instead; the gap is filled by scrolling up.
+// Returns true if any messages were fetched.
+func (app *ChatApp) backfillChannel(session *revoltgo.Session, channelID, afterID string) bool {
+	fetched := false
+	for range backfillMaxPages {
+		page, err := session.ChannelMessages(channelID, revoltgo.ChannelMessagesParams{
+			After:        afterID,
+			Sort:         revoltgo.ChannelMessagesParamsSortTypeOldest,
+			Limit:        backfillPageSize,
+			IncludeUsers: true,
+		})
+		if err != nil {
+			log.Printf("Failed to backfill messages of %s: %v\n", channelID, err)
+			return fetched
+		}
+
+		caughtUp := len(page.Messages) < backfillPageSize
+		app.Messages.Extend(channelID, afterID, page.Messages, caughtUp)
+		fetched = fetched || len(page.Messages) > 0
+		if caughtUp {
+			return fetched
+		}
+		afterID = page.Messages[len(page.Messages)-1].ID
+	}
+
+	latest, err := session.ChannelMessages(channelID, revoltgo.ChannelMessagesParams{
+		Limit:        backfillPageSize,
+		IncludeUsers: true,
+	})
+	if err != nil {
+		log.Printf("Failed to fetch latest messages of %s: %v\n", channelID, err)
+		return fetched
+	}
+
+	app.Messages.Set(channelID, latest.Messages)
+	return true
+}
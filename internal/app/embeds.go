@@ -0,0 +1,82 @@
+package app
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/sentinelb51/revoltgo"
+)
+
+// embedRefetchDelays are the waits before each re-fetch of a message whose link embeds have not arrived.
+// The server sends them as a MessageAppend event, which revoltgo cannot deliver (see registerEventHandlers).
+var embedRefetchDelays = []time.Duration{3 * time.Second, 10 * time.Second}
+
+// awaitEmbeds re-fetches a message that arrived without embeds, if its content has links,
+// until the server has generated them.
+func (app *ChatApp) awaitEmbeds(channelID, messageID, content string) {
+	if app.Session == nil || !hasLink(content) {
+		return
+	}
+
+	go func() {
+		for _, delay := range embedRefetchDelays {
+			time.Sleep(delay)
+			if !app.Messages.Contains(channelID, messageID) {
+				return // Deleted or evicted meanwhile
+			}
+
+			fetched, err := app.Session.ChannelMessage(channelID, messageID)
+			if err != nil || fetched == nil {
+				log.Printf("Failed to re-fetch message %s for embeds: %v\n", messageID, err)
+				return
+			}
+			if len(fetched.Embeds) > 0 {
+				app.showFetchedMessage(channelID, fetched)
+				return
+			}
+		}
+	}()
+}
+
+// refreshMessage re-fetches a cached message, for updates whose changes the event does not carry.
+func (app *ChatApp) refreshMessage(channelID, messageID string) {
+	if app.Session == nil || !app.Messages.Contains(channelID, messageID) {
+		return
+	}
+
+	go func() {
+		fetched, err := app.Session.ChannelMessage(channelID, messageID)
+		if err != nil || fetched == nil {
+			log.Printf("Failed to refresh message %s: %v\n", messageID, err)
+			return
+		}
+		app.showFetchedMessage(channelID, fetched)
+	}()
+}
+
+// showFetchedMessage replaces a cached message with its fetched copy and re-renders it if shown.
+func (app *ChatApp) showFetchedMessage(channelID string, msg *revoltgo.Message) {
+	app.References.Update(msg)
+	if !app.Messages.Update(channelID, msg) {
+		return
+	}
+
+	app.GoDo(func() {
+		if channelID == app.CurrentChannelID {
+			app.updateMessageWidget(msg)
+		}
+	}, false)
+}
+
+// hasLink reports whether message content contains a link the server may embed.
+func hasLink(content string) bool {
+	return strings.Contains(content, "https://") || strings.Contains(content, "http://")
+}
+
+// isClearUpdate reports whether a message update carries no fields. The server sends these when it
+// only clears fields (such as Pinned or Embeds); revoltgo does not decode the list of cleared fields.
+func isClearUpdate(partial *revoltgo.Message) bool {
+	return partial.Content == "" && partial.Edited == nil && partial.Embeds == nil &&
+		partial.Reactions == nil && partial.Interactions == nil && !partial.Pinned
+}
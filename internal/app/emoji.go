@@ -0,0 +1,82 @@
+package app
+
+import (
+	"slices"
+
+	"github.com/sentinelb51/revoltgo"
+
+	"RGOClient/internal/emoji"
+)
+
+// Maximum number of emoji offered by shortcode completion.
+const maxEmojiSuggestions = 8
+
+// onEmojiCreate tracks a custom emoji added to one of our servers.
+func (app *ChatApp) onEmojiCreate(_ *revoltgo.Session, event *revoltgo.EventEmojiCreate) {
+	id := event.Emoji.ID
+	app.GoDo(func() {
+		if !slices.Contains(app.EmojiIDs, id) {
+			app.EmojiIDs = append(app.EmojiIDs, id)
+		}
+	}, false)
+}
+
+// onEmojiDelete forgets a deleted custom emoji.
+func (app *ChatApp) onEmojiDelete(_ *revoltgo.Session, event *revoltgo.EventEmojiDelete) {
+	id := event.ID
+	app.GoDo(func() {
+		app.EmojiIDs = slices.DeleteFunc(app.EmojiIDs, func(e string) bool {
+			return e == id
+		})
+	}, false)
+}
+
+// CustomEmoji returns custom emoji grouped by server, in server list order.
+func (app *ChatApp) CustomEmoji() []emoji.Category {
+	if app.Session == nil {
+		return nil
+	}
+
+	byServer := make(map[string][]emoji.Emoji)
+	for _, id := range app.EmojiIDs {
+		e := app.Session.State.Emoji(id)
+		if e == nil || e.Parent == nil {
+			continue
+		}
+		byServer[e.Parent.ID] = append(byServer[e.Parent.ID], emoji.Emoji{Value: e.ID, Name: e.Name, Custom: true})
+	}
+
+	var categories []emoji.Category
+	for _, serverID := range app.ServerIDs {
+		list := byServer[serverID]
+		if len(list) == 0 {
+			continue
+		}
+
+		name := serverID
+		if server := app.Session.State.Server(serverID); server != nil {
+			name = server.Name
+		}
+		categories = append(categories, emoji.Category{Name: name, Emoji: list})
+	}
+	return categories
+}
+
+// emojiCandidates returns emoji matching a shortcode query, for shortcode completion.
+// Custom emoji from the current server come first.
+func (app *ChatApp) emojiCandidates(query string) []emoji.Emoji {
+	var current, others []emoji.Emoji
+	for _, category := range app.CustomEmoji() {
+		for _, e := range category.Emoji {
+			if parent := app.Session.State.Emoji(e.Value); parent != nil && parent.Parent.ID == app.CurrentServerID {
+				current = append(current, e)
+			} else {
+				others = append(others, e)
+			}
+		}
+	}
+
+	pool := append(current, others...)
+	pool = append(pool, emoji.All()...)
+	return emoji.Search(query, pool, maxEmojiSuggestions)
+}
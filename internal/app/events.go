@@ -20,6 +20,7 @@ func (app *ChatApp) StartRevoltSessionWithToken(token string) error {
 	if err := app.Session.Open(); err != nil {
 		return fmt.Errorf("failed to open session: %w", err)
 	}
+	app.startConnection(session)
 	return nil
 }
 
@@ -45,6 +46,7 @@ func (app *ChatApp) StartRevoltSessionWithLogin(email, password string) (string,
 	if err := app.Session.Open(); err != nil {
 		return "", fmt.Errorf("failed to open session: %w", err)
 	}
+	app.startConnection(session)
 
 	return resp.Token, nil
 }
@@ -53,42 +55,85 @@ func (app *ChatApp) StartRevoltSessionWithLogin(email, password string) (string,
 func (app *ChatApp) registerEventHandlers(session *revoltgo.Session) {
 	revoltgo.AddHandler(session, app.onReady)
 	revoltgo.AddHandler(session, app.onMessage)
+	revoltgo.AddHandler(session, app.onMessageUpdate)
+	revoltgo.AddHandler(session, app.onMessageDelete)
+	revoltgo.AddHandler(session, app.onBulkMessageDelete)
+	revoltgo.AddHandler(session, app.onMessageReact)
+	revoltgo.AddHandler(session, app.onMessageUnreact)
+	revoltgo.AddHandler(session, app.onChannelStartTyping)
+	revoltgo.AddHandler(session, app.onChannelStopTyping)
+	revoltgo.AddHandler(session, app.onEmojiCreate)
+	revoltgo.AddHandler(session, app.onEmojiDelete)
+	revoltgo.AddHandler(session, app.onServerMemberJoin)
+	revoltgo.AddHandler(session, app.onServerMemberLeave)
+	revoltgo.AddHandler(session, app.onServerMemberUpdate)
+	revoltgo.AddHandler(session, app.onUserUpdate)
+	revoltgo.AddHandler(session, app.onChannelCreate)
+	revoltgo.AddHandler(session, app.onChannelDelete)
+	revoltgo.AddHandler(session, app.onChannelGroupLeave)
+	revoltgo.AddHandler(session, app.onUserRelationship)
+	// MessageAppend is not handled: revoltgo's EventMessageAppend lacks the embedded Event type field,
+	// and AddHandler rejects such structs. Late link embeds are re-fetched instead (see awaitEmbeds).
+	// EventMessageRemoveReaction has the same problem; cleared reactions show up on the next fetch.
 	revoltgo.AddHandler(session, app.onError)
+	revoltgo.AddHandler(session, app.onLogout)
 }
 
 // onError handles error events from the websocket.
+// Only an invalid session ends it; other errors close the websocket, which is then reconnected.
 func (app *ChatApp) onError(_ *revoltgo.Session, event *revoltgo.EventError) {
 	log.Printf("Error event: %s\n", event.Data.Type)
 
-	if event.Data.Type == revoltgo.EventErrorInvalidSession ||
-		event.Data.Type == revoltgo.EventErrorInternalError {
+	if event.Data.Type == revoltgo.EventErrorInvalidSession {
+		app.endSession()
+	}
+}
 
-		// Remove invalid session
-		if app.Session != nil && app.Session.State != nil {
-			if self := app.Session.State.Self(); self != nil {
-				if err := RemoveSession(self.ID); err != nil {
-					log.Printf("Failed to remove session: %v\n", err)
-				}
-			}
-		}
+// onLogout ends the session when it is logged out, e.g. from another device.
+func (app *ChatApp) onLogout(_ *revoltgo.Session, _ *revoltgo.EventLogout) {
+	app.endSession()
+}
+
+// endSession forgets the saved token of a session that is no longer valid and shows the login screen.
+func (app *ChatApp) endSession() {
+	app.endConnection()
 
-		// Close session and show login
-		if app.Session != nil {
-			_ = app.Session.Close()
-			app.Session = nil
-			context.ClearSession() // Clear global session context
+	// Remove invalid session
+	if app.Session != nil && app.Session.State != nil {
+		if self := app.Session.State.Self(); self != nil {
+			if err := RemoveSession(self.ID); err != nil {
+				log.Printf("Failed to remove session: %v\n", err)
+			}
 		}
+	}
 
-		app.GoDo(func() {
-			app.ShowLoginWindow()
-		}, true)
+	// Close session and show login
+	if app.Session != nil {
+		_ = app.Session.Close()
+		app.Session = nil
+		context.ClearSession() // Clear global session context
 	}
+	app.Messages.CloseStore()
+
+	app.GoDo(func() {
+		app.closeOutbox()
+		app.sessionReady = false
+		app.setReconnecting(false)
+		app.ShowLoginWindow()
+	}, true)
 }
 
-// onReady handles the Ready event when connected.
+// onReady handles the Ready event when connected, and again after every reconnect.
 func (app *ChatApp) onReady(_ *revoltgo.Session, event *revoltgo.EventReady) {
 	fmt.Printf("Ready: %d user(s), %d server(s)\n", len(event.Users), len(event.Servers))
 
+	// Open the disk message store of this account
+	if self := app.Session.State.Self(); self != nil {
+		if err := app.Messages.OpenStore(self.ID, app.messageRetention()); err != nil {
+			log.Printf("Failed to open message store: %v\n", err)
+		}
+	}
+
 	// Save pending session token
 	if token := app.GetPendingSessionToken(); token != "" {
 		if self := app.Session.State.Self(); self != nil {
@@ -111,46 +156,105 @@ func (app *ChatApp) onReady(_ *revoltgo.Session, event *revoltgo.EventReady) {
 	// Fetch unreads asynchronously
 	go func() {
 		app.GoDo(func() {
-			// Populate unread map
-			for _, u := range event.ChannelUnreads {
-				app.UnreadChannels[u.ID.Channel] = true
+			if app.sessionReady {
+				app.onReconnected(event)
+				return
 			}
+			app.sessionReady = true
+			app.setReconnecting(false) // The first connection attempt may have failed
+			if self := app.Session.State.Self(); self != nil {
+				app.openOutbox(self.ID)
+			}
+			app.loadUploadLimits(app.Session)
 
+			app.applyReady(event)
 			app.SwitchToMainUI()
+			app.RefreshServerList()
+			app.selectInitial()
+		}, true)
+	}()
+}
 
-			// Store server IDs
-			app.ServerIDs = make([]string, 0, len(event.Servers))
-			for _, server := range event.Servers {
-				app.ServerIDs = append(app.ServerIDs, server.ID)
-			}
+// applyReady replaces the unreads, servers, emojis, private channels and relationships with those of a Ready payload.
+func (app *ChatApp) applyReady(event *revoltgo.EventReady) {
+	// Populate unread map
+	app.UnreadChannels = make(map[string]bool, len(event.ChannelUnreads))
+	for _, u := range event.ChannelUnreads {
+		app.UnreadChannels[u.ID.Channel] = true
+	}
 
-			app.RefreshServerList()
+	// Store server IDs
+	app.ServerIDs = make([]string, 0, len(event.Servers))
+	for _, server := range event.Servers {
+		app.ServerIDs = append(app.ServerIDs, server.ID)
+	}
 
-			// Select first server and channel
-			if len(app.ServerIDs) > 0 {
-				app.CurrentServerID = app.ServerIDs[0]
-				app.updateServerSelectionUI(app.CurrentServerID)
+	// Store custom emoji IDs
+	app.EmojiIDs = make([]string, 0, len(event.Emojis))
+	for _, e := range event.Emojis {
+		app.EmojiIDs = append(app.EmojiIDs, e.ID)
+	}
 
-				if server := app.CurrentServer(); server != nil {
-					app.updateServerHeader(server.Name)
-					app.RefreshChannelList()
+	app.setPrivateChannels(event.Channels)
+	app.setRelationships()
+}
 
-					if len(server.Channels) > 0 {
-						app.SelectChannel(server.Channels[0])
-					}
-				}
-			}
-		}, true)
-	}()
+// selectInitial selects the first server and its first channel; without servers, it starts on Home.
+func (app *ChatApp) selectInitial() {
+	if len(app.ServerIDs) == 0 {
+		app.SelectHome()
+		return
+	}
+
+	app.CurrentServerID = app.ServerIDs[0]
+	app.updateServerSelectionUI(app.CurrentServerID)
+
+	if server := app.CurrentServer(); server != nil {
+		app.updateServerHeader(server.Name)
+		app.RefreshChannelList()
+		app.syncMemberListPanel()
+
+		if len(server.Channels) > 0 {
+			app.SelectChannel(server.Channels[0])
+		}
+	}
 }
 
 // onMessage handles incoming messages from the websocket.
 func (app *ChatApp) onMessage(_ *revoltgo.Session, event *revoltgo.EventMessage) {
 	// Clone message to prevent pointer reuse issues if the event is pooled
 	msg := event.Message
-	app.Messages.Append(event.Channel, &msg)
+	added := app.Messages.Append(event.Channel, &msg)
+	if len(msg.Embeds) == 0 {
+		app.awaitEmbeds(event.Channel, msg.ID, msg.Content)
+	}
+	pinned := app.applyPinEvent(event.Channel, &msg)
 
 	app.GoDo(func() {
+		// A sent message ends the author's typing state
+		app.setTyping(event.Channel, msg.Author, false)
+
+		// Our own message replaces its local copy, in the same frame so it is never shown twice
+		app.reconcileSent(msg.Nonce)
+
+		if pinned != nil && event.Channel == app.CurrentChannelID {
+			app.updateMessageWidget(pinned)
+		}
+
+		// Already shown: the server's copy of our message, added when the echo was late
+		if !added {
+			if event.Channel == app.CurrentChannelID {
+				app.updateMessageWidget(&msg)
+			}
+			return
+		}
+
+		// A message makes a DM active, so it moves to (or joins) the top of the Home list
+		if channel := app.channel(event.Channel); channel != nil && channel.Server == nil &&
+			channel.ChannelType != revoltgo.ChannelTypeSavedMessages {
+			app.addPrivateChannel(channel)
+		}
+
 		if event.Channel != app.CurrentChannelID {
 			app.UnreadChannels[event.Channel] = true
 			app.syncChannelListUI()
@@ -160,3 +264,64 @@ func (app *ChatApp) onMessage(_ *revoltgo.Session, event *revoltgo.EventMessage)
 		app.AddMessage(&msg)
 	}, false)
 }
+
+// onMessageUpdate applies edits made to a message (content, embeds, reactions).
+func (app *ChatApp) onMessageUpdate(_ *revoltgo.Session, event *revoltgo.EventMessageUpdate) {
+	// Only cleared fields changed, and the event does not say which: fetch the message instead
+	if isClearUpdate(&event.Data) {
+		app.refreshMessage(event.Channel, event.ID)
+		return
+	}
+
+	// Messages only fetched as reply targets are updated too, so their previews do not go stale
+	referenced := app.References.Merge(event.ID, &event.Data)
+	msg := app.Messages.Merge(event.Channel, event.ID, &event.Data)
+	if msg == nil && referenced == nil {
+		return
+	}
+	if msg != nil && event.Data.Content != "" && len(event.Data.Embeds) == 0 {
+		app.awaitEmbeds(event.Channel, msg.ID, msg.Content)
+	}
+
+	app.GoDo(func() {
+		if event.Channel != app.CurrentChannelID {
+			return
+		}
+		if msg != nil {
+			app.updateMessageWidget(msg)
+		}
+		app.refreshReplyPreviews(event.ID)
+	}, false)
+}
+
+// applyPinEvent marks the message a pin or unpin system message refers to.
+// Returns the updated message, or nil if it is not such a message or the target is not cached.
+func (app *ChatApp) applyPinEvent(channelID string, msg *revoltgo.Message) *revoltgo.Message {
+	if msg.System == nil {
+		return nil
+	}
+
+	switch msg.System.Type {
+	case revoltgo.MessageSystemMessagePinned:
+		return app.Messages.SetPinned(channelID, msg.System.ID, true)
+	case revoltgo.MessageSystemMessageUnpinned:
+		return app.Messages.SetPinned(channelID, msg.System.ID, false)
+	}
+	return nil
+}
+
+// onMessageDelete removes a deleted message from the cache and the visible list.
+func (app *ChatApp) onMessageDelete(_ *revoltgo.Session, event *revoltgo.EventMessageDelete) {
+	app.GoDo(func() {
+		app.removeMessage(event.Channel, event.ID)
+	}, false)
+}
+
+// onBulkMessageDelete removes several deleted messages at once.
+func (app *ChatApp) onBulkMessageDelete(_ *revoltgo.Session, event *revoltgo.EventBulkMessageDelete) {
+	app.GoDo(func() {
+		for _, id := range event.IDs {
+			app.removeMessage(event.Channel, id)
+		}
+	}, false)
+}
@@ -0,0 +1,248 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"fyne.io/fyne/v2/dialog"
+	"github.com/sentinelb51/revoltgo"
+
+	"RGOClient/internal/ui/widgets"
+	"RGOClient/internal/util"
+)
+
+// friendsEntryIcon is the icon of the Friends entry at the top of the Home list.
+const friendsEntryIcon = "assets/members.svg"
+
+// ShowFriends replaces the message area with the friends panel.
+func (app *ChatApp) ShowFriends() {
+	if app.messageInput != nil {
+		app.messageInput.StopTyping()
+	}
+	app.clearChannelSelection()
+	app.setFriendsVisible(true)
+	app.syncChannelListUI()
+}
+
+// setFriendsVisible toggles between the friends panel and the message area.
+func (app *ChatApp) setFriendsVisible(visible bool) {
+	app.friendsVisible = visible
+	if app.friendsPanel == nil {
+		return
+	}
+
+	if visible {
+		app.friendsPanel.SetRows(app.friendRows())
+		app.friendsPanel.Show()
+		app.chatView.Hide()
+	} else {
+		app.friendsPanel.Hide()
+		app.chatView.Show()
+	}
+}
+
+// setRelationships stores the relationships the Ready payload put on the current user.
+func (app *ChatApp) setRelationships() {
+	app.relationships = make(map[string]revoltgo.UserRelationshipType)
+	app.relationshipUsers = make(map[string]*revoltgo.User)
+
+	self := app.Session.State.Self()
+	if self == nil {
+		return
+	}
+
+	for _, relation := range self.Relations {
+		if hasRelationship(relation.Status) {
+			app.relationships[relation.ID] = relation.Status
+		}
+	}
+}
+
+// relationship returns the current user's relationship with a user.
+// Gateway updates take precedence over the state, which does not apply them.
+func (app *ChatApp) relationship(userID string) revoltgo.UserRelationshipType {
+	if relationship, ok := app.relationships[userID]; ok {
+		return relationship
+	}
+	if user := app.Session.State.User(userID); user != nil {
+		return user.Relationship
+	}
+	return revoltgo.UserRelationsTypeNone
+}
+
+// setRelationship applies a user's new relationship from a gateway event or API response.
+func (app *ChatApp) setRelationship(user *revoltgo.User) {
+	if hasRelationship(user.Relationship) {
+		app.relationships[user.ID] = user.Relationship
+		if app.Session.State.User(user.ID) == nil {
+			app.relationshipUsers[user.ID] = user
+		}
+	} else {
+		app.relationships[user.ID] = revoltgo.UserRelationsTypeNone
+		delete(app.relationshipUsers, user.ID)
+	}
+
+	app.refreshFriends()
+}
+
+// hasRelationship reports whether a relationship is listed by the friends panel or profile card.
+func hasRelationship(relationship revoltgo.UserRelationshipType) bool {
+	return relationship != "" && relationship != revoltgo.UserRelationsTypeNone &&
+		relationship != revoltgo.UserRelationsTypeUser
+}
+
+// incomingRequests returns the number of pending incoming friend requests.
+func (app *ChatApp) incomingRequests() int {
+	count := 0
+	for _, relationship := range app.relationships {
+		if relationship == revoltgo.UserRelationsTypeIncoming {
+			count++
+		}
+	}
+	return count
+}
+
+// refreshFriends updates the friends panel (if shown) and the Friends entry's pending indicator.
+func (app *ChatApp) refreshFriends() {
+	if app.friendsVisible && app.friendsPanel != nil {
+		app.friendsPanel.SetRows(app.friendRows())
+	}
+	app.syncChannelListUI()
+}
+
+// friendRows describes every user the current user has a relationship with.
+func (app *ChatApp) friendRows() []widgets.FriendRow {
+	rows := make([]widgets.FriendRow, 0, len(app.relationships))
+	for userID, relationship := range app.relationships {
+		if !hasRelationship(relationship) {
+			continue
+		}
+
+		user := app.Session.State.User(userID)
+		if user == nil {
+			user = app.relationshipUsers[userID]
+		}
+		if user == nil {
+			continue
+		}
+
+		row := widgets.FriendRow{
+			UserID:       userID,
+			Name:         user.Username,
+			Username:     util.FullUsername(user.Username, user.Discriminator),
+			AvatarURL:    user.AvatarURL("64"),
+			Online:       user.Online,
+			Relationship: relationship,
+		}
+		if user.DisplayName != nil && *user.DisplayName != "" {
+			row.Name = *user.DisplayName
+		}
+		if user.Status != nil {
+			row.Presence = user.Status.Presence
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// friendActions wires the friends panel to the relationship API.
+func (app *ChatApp) friendActions() widgets.FriendActions {
+	return widgets.FriendActions{
+		OnSendRequest: app.sendFriendRequest,
+		OnAccept: func(userID string) {
+			app.updateRelationship(userID, app.Session.FriendAdd)
+		},
+		OnRemove: func(userID string) {
+			if app.relationship(userID) != revoltgo.UserRelationsTypeFriend {
+				app.updateRelationship(userID, app.Session.FriendDelete) // Deny or cancel a request
+				return
+			}
+			app.confirmRelationship("Remove friend", "Remove", userID, app.Session.FriendDelete)
+		},
+		OnBlock: func(userID string) {
+			app.confirmRelationship("Block user", "Block", userID, app.Session.UserBlock)
+		},
+		OnUnblock: func(userID string) {
+			app.updateRelationship(userID, app.Session.UserUnblock)
+		},
+		OnMessage: app.openDirectMessage,
+		OnProfile: app.OnAvatarTapped,
+	}
+}
+
+// confirmRelationship asks before removing a friend or blocking a user.
+// Holding shift while clicking skips the confirmation.
+func (app *ChatApp) confirmRelationship(title, action, userID string, call func(string) (*revoltgo.User, error)) {
+	if isShiftHeld() {
+		app.updateRelationship(userID, call)
+		return
+	}
+
+	name := userID
+	if user := app.Session.State.User(userID); user != nil {
+		name = user.Username
+	}
+
+	message := fmt.Sprintf("Are you sure you want to %s %s?", strings.ToLower(action), name)
+	dialog.ShowConfirm(title, message, func(ok bool) {
+		if ok {
+			app.updateRelationship(userID, call)
+		}
+	}, app.window)
+}
+
+// updateRelationship runs a relationship API call in the background and applies the returned user.
+// The gateway sends the same change as a UserRelationship event; applying both is harmless.
+func (app *ChatApp) updateRelationship(userID string, call func(string) (*revoltgo.User, error)) {
+	go func() {
+		user, err := call(userID)
+		if err != nil {
+			fmt.Printf("Failed to update relationship with %s: %v\n", userID, err)
+			return
+		}
+
+		if user != nil {
+			app.GoDo(func() {
+				app.setRelationship(user)
+			}, false)
+		}
+	}()
+}
+
+// sendFriendRequest sends a friend request by username ("name#1234") and reports the outcome on the panel.
+func (app *ChatApp) sendFriendRequest(username string) {
+	go func() {
+		// revoltgo's FriendAdd only takes user IDs; requests by username go to the bare endpoint
+		body := struct {
+			Username string `json:"username"`
+		}{username}
+
+		var user *revoltgo.User
+		err := app.Session.HTTP.Request(http.MethodPost, revoltgo.EndpointUserFriend(""), body, &user)
+
+		app.GoDo(func() {
+			if err != nil {
+				app.friendsPanel.SetStatus(fmt.Sprintf("Could not send a friend request to %s: %v", username, err), true)
+				return
+			}
+
+			app.friendsPanel.SetStatus(fmt.Sprintf("Friend request sent to %s.", username), false)
+			if user != nil {
+				app.setRelationship(user)
+			}
+		}, false)
+	}()
+}
+
+// onUserRelationship applies friend requests, removals and blocks made on any device or by other users.
+func (app *ChatApp) onUserRelationship(_ *revoltgo.Session, event *revoltgo.EventUserRelationship) {
+	if event.User == nil {
+		return
+	}
+
+	user := event.User
+	app.GoDo(func() {
+		app.setRelationship(user)
+	}, false)
+}
@@ -0,0 +1,224 @@
+package app
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/sentinelb51/revoltgo"
+
+	"RGOClient/internal/ui/widgets"
+)
+
+// homeHeader is the channel sidebar header shown while direct messages are listed.
+const homeHeader = "Direct Messages"
+
+// SelectHome switches the channel list to direct messages and group DMs.
+// Home uses an empty CurrentServerID; the channels go through the same selection and message loading as server channels.
+func (app *ChatApp) SelectHome() {
+	app.showHome()
+
+	if len(app.PrivateChannelIDs) > 0 {
+		app.SelectChannel(app.PrivateChannelIDs[0])
+	} else {
+		app.ShowFriends()
+	}
+
+	app.RefreshChannelList()
+}
+
+// showHome switches the sidebars to Home without selecting a channel.
+func (app *ChatApp) showHome() {
+	app.CurrentServerID = ""
+	app.updateServerSelectionUI("")
+	app.updateServerHeader(homeHeader)
+	app.syncMemberListPanel()
+}
+
+// channel returns a channel from the session state, falling back to private channels opened this session.
+func (app *ChatApp) channel(channelID string) *revoltgo.Channel {
+	if channel := app.Session.State.Channel(channelID); channel != nil {
+		return channel
+	}
+	return app.privateChannels[channelID]
+}
+
+// isPrivateChannel reports whether a channel belongs on the Home list.
+// Direct messages that were never used (or were closed) are inactive and stay hidden.
+func isPrivateChannel(channel *revoltgo.Channel) bool {
+	switch channel.ChannelType {
+	case revoltgo.ChannelTypeDM:
+		return channel.Active
+	case revoltgo.ChannelTypeGroup:
+		return true
+	}
+	return false
+}
+
+// setPrivateChannels stores the private channels from the Ready payload, most recently active first.
+func (app *ChatApp) setPrivateChannels(channels []*revoltgo.Channel) {
+	private := make([]*revoltgo.Channel, 0)
+	for _, channel := range channels {
+		if isPrivateChannel(channel) {
+			private = append(private, channel)
+		}
+	}
+
+	// IDs are ULIDs, so comparing them orders by time
+	lastActivity := func(c *revoltgo.Channel) string {
+		if c.LastMessageID != nil {
+			return *c.LastMessageID
+		}
+		return c.ID
+	}
+	slices.SortFunc(private, func(a, b *revoltgo.Channel) int {
+		return cmp.Compare(lastActivity(b), lastActivity(a))
+	})
+
+	app.PrivateChannelIDs = make([]string, 0, len(private))
+	for _, channel := range private {
+		app.PrivateChannelIDs = append(app.PrivateChannelIDs, channel.ID)
+	}
+}
+
+// addPrivateChannel moves a private channel to the top of the Home list, adding it if new.
+// Channels missing from the session state (e.g. DMs opened through the API) are kept as a fallback.
+func (app *ChatApp) addPrivateChannel(channel *revoltgo.Channel) {
+	if app.Session.State.Channel(channel.ID) == nil {
+		app.privateChannels[channel.ID] = channel
+	}
+
+	if index := slices.Index(app.PrivateChannelIDs, channel.ID); index == 0 {
+		return
+	} else if index > 0 {
+		app.PrivateChannelIDs = slices.Delete(app.PrivateChannelIDs, index, index+1)
+	}
+	app.PrivateChannelIDs = slices.Insert(app.PrivateChannelIDs, 0, channel.ID)
+
+	if app.CurrentServerID == "" {
+		app.RefreshChannelList()
+	}
+}
+
+// removePrivateChannel drops a channel from the Home list, leaving Home's selection if it was open.
+func (app *ChatApp) removePrivateChannel(channelID string) {
+	index := slices.Index(app.PrivateChannelIDs, channelID)
+	if index < 0 {
+		return
+	}
+
+	app.PrivateChannelIDs = slices.Delete(app.PrivateChannelIDs, index, index+1)
+	delete(app.privateChannels, channelID)
+
+	if app.CurrentChannelID == channelID {
+		app.clearChannelSelection()
+	}
+	if app.CurrentServerID == "" {
+		app.RefreshChannelList()
+	}
+}
+
+// refreshPrivateChannelList rebuilds the channel list with the Home entries: Friends, then DMs and groups.
+func (app *ChatApp) refreshPrivateChannelList() {
+	friends := widgets.NewDirectMessageWidget(widgets.DirectMessageInfo{Name: "Friends", IconPath: friendsEntryIcon}, app.ShowFriends)
+	friends.SetState(app.friendsVisible, app.incomingRequests() > 0)
+	app.channelListContainer.Add(friends)
+
+	for _, channelID := range app.PrivateChannelIDs {
+		channel := app.channel(channelID)
+		if channel == nil {
+			continue
+		}
+
+		capturedID := channelID
+		w := widgets.NewDirectMessageWidget(app.directMessageInfo(channel), func() {
+			app.SelectChannel(capturedID)
+		})
+		w.SetState(capturedID == app.CurrentChannelID, app.UnreadChannels[capturedID])
+		app.channelListContainer.Add(w)
+	}
+}
+
+// directMessageInfo describes a private channel for its Home list entry.
+func (app *ChatApp) directMessageInfo(channel *revoltgo.Channel) widgets.DirectMessageInfo {
+	info := widgets.DirectMessageInfo{
+		ChannelID: channel.ID,
+		Name:      app.channelDisplayName(channel),
+		IsGroup:   channel.ChannelType == revoltgo.ChannelTypeGroup,
+	}
+
+	if info.IsGroup {
+		info.Subtitle = fmt.Sprintf("%d members", len(channel.Recipients))
+		if channel.Icon != nil {
+			info.AvatarURL = channel.Icon.URL("64")
+		}
+		return info
+	}
+
+	if user := app.recipient(channel); user != nil {
+		info.AvatarURL = user.AvatarURL("64")
+		info.Online = user.Online
+		if user.Status != nil {
+			info.Presence = user.Status.Presence
+			info.Subtitle = user.Status.Text
+		}
+	}
+	return info
+}
+
+// recipient returns the other user of a direct message, or nil if unknown.
+func (app *ChatApp) recipient(channel *revoltgo.Channel) *revoltgo.User {
+	self := app.Session.State.Self()
+	for _, id := range channel.Recipients {
+		if self != nil && id == self.ID {
+			continue
+		}
+		return app.Session.State.User(id)
+	}
+	return nil
+}
+
+// isRecipient reports whether a user takes part in any listed private channel.
+func (app *ChatApp) isRecipient(userID string) bool {
+	for _, channelID := range app.PrivateChannelIDs {
+		if channel := app.channel(channelID); channel != nil && slices.Contains(channel.Recipients, userID) {
+			return true
+		}
+	}
+	return false
+}
+
+// onChannelCreate adds newly opened DMs and groups to the Home list.
+func (app *ChatApp) onChannelCreate(_ *revoltgo.Session, event *revoltgo.EventChannelCreate) {
+	channel := event.Channel
+	if !isPrivateChannel(&channel) {
+		return
+	}
+
+	app.GoDo(func() {
+		app.addPrivateChannel(&channel)
+	}, false)
+}
+
+// onChannelDelete removes deleted groups and closed DMs from the Home list.
+func (app *ChatApp) onChannelDelete(_ *revoltgo.Session, event *revoltgo.EventChannelDelete) {
+	channelID := event.ID
+	app.GoDo(func() {
+		app.removePrivateChannel(channelID)
+	}, false)
+}
+
+// onChannelGroupLeave removes a group from the Home list when the current user leaves it,
+// and refreshes the member count otherwise.
+func (app *ChatApp) onChannelGroupLeave(_ *revoltgo.Session, event *revoltgo.EventChannelGroupLeave) {
+	channelID, userID := event.ID, event.User
+	app.GoDo(func() {
+		if self := app.Session.State.Self(); self != nil && self.ID == userID {
+			app.removePrivateChannel(channelID)
+			return
+		}
+		if app.CurrentServerID == "" {
+			app.RefreshChannelList()
+		}
+	}, false)
+}
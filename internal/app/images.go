@@ -0,0 +1,79 @@
+package app
+
+import (
+	"slices"
+
+	"github.com/sentinelb51/revoltgo"
+
+	"RGOClient/internal/cache"
+	"RGOClient/internal/ui/widgets"
+	"RGOClient/internal/util"
+)
+
+// ImageScope returns the scope of the shown messages' image loads.
+// Implements interfaces.MessageActions.
+func (app *ChatApp) ImageScope() *cache.ImageScope {
+	return app.imageScope
+}
+
+// replaceImageScope starts a scope for messages about to be rendered and prefetches their images,
+// then cancels the loads of the messages they replace. Downloads both want carry on.
+func (app *ChatApp) replaceImageScope(messages []*revoltgo.Message) {
+	previous := app.imageScope
+	app.imageScope = cache.GetImageCache().NewScope()
+	app.visibleMessageIDs = nil
+	app.prefetchMessageImages(messages)
+	previous.Cancel()
+}
+
+// prefetchMessageImages queues the avatars and image attachments of messages about to be rendered,
+// newest first, since the newest are shown first. Rendering them raises their priority.
+func (app *ChatApp) prefetchMessageImages(messages []*revoltgo.Message) {
+	for i := len(messages) - 1; i >= 0; i-- {
+		message := messages[i]
+		owner := app.imageScope.Owner(message.ID)
+
+		// Only avatars with an ID are loaded by the message widget
+		avatarURL := util.DisplayAvatarURL(message)
+		if avatarID := util.IDFromAttachmentURL(avatarURL); avatarID != "" {
+			owner.Prefetch(avatarID, avatarURL)
+		}
+		for _, attachment := range message.Attachments {
+			if attachment.Metadata.Type == revoltgo.AttachmentMetadataTypeImage && attachment.ID != "" {
+				owner.Prefetch(attachment.ID, attachment.URL(""))
+			}
+		}
+	}
+}
+
+// prioritizeVisibleImages moves the image loads of the messages on screen ahead of the others.
+// Called on every scroll, so the queue is only reordered when the messages on screen change.
+func (app *ChatApp) prioritizeVisibleImages() {
+	if app.messageScroll == nil {
+		return
+	}
+
+	top := app.messageScroll.Offset.Y
+	bottom := top + app.messageScroll.Size().Height
+
+	var visible []string
+	for _, obj := range app.messageListContainer.Objects {
+		w, ok := obj.(*widgets.MessageWidget)
+		if !ok {
+			continue
+		}
+		y := w.Position().Y
+		if y > bottom {
+			break // Widgets are laid out top to bottom
+		}
+		if y+w.Size().Height >= top {
+			visible = append(visible, w.Message.ID)
+		}
+	}
+
+	if slices.Equal(visible, app.visibleMessageIDs) {
+		return
+	}
+	app.visibleMessageIDs = visible
+	app.imageScope.SetVisible(visible)
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"github.com/sentinelb51/revoltgo"
+
+	"RGOClient/internal/ui/theme"
+	"RGOClient/internal/ui/widgets"
+)
+
+// Number of messages fetched on either side of a jump target.
+const jumpWindowSize = 50
+
+// OnReplyTapped jumps to the message a reply refers to.
+func (app *ChatApp) OnReplyTapped(channelID, messageID string) {
+	if channelID != app.CurrentChannelID || app.Messages.IsDeleted(messageID) {
+		return
+	}
+
+	app.jumpToMessage(channelID, messageID)
+}
+
+// jumpToMessage scrolls to a message if it is rendered.
+// Otherwise, a window of history around it is fetched and shown in place of live messages.
+func (app *ChatApp) jumpToMessage(channelID, messageID string) {
+	if _, w := app.findMessageWidget(messageID); w != nil {
+		app.scrollToMessage(w)
+		return
+	}
+
+	if app.Session == nil {
+		return
+	}
+
+	go func() {
+		// Nearby ignores Before/After/Sort; the order of the result is not guaranteed
+		window, err := app.Session.ChannelMessages(channelID, revoltgo.ChannelMessagesParams{
+			Nearby:       messageID,
+			Limit:        jumpWindowSize,
+			IncludeUsers: true,
+		})
+
+		if err != nil {
+			fmt.Printf("Failed to fetch messages around %s: %v\n", messageID, err)
+			return
+		}
+
+		messages := window.Messages
+		slices.SortFunc(messages, func(a, b *revoltgo.Message) int {
+			return strings.Compare(a.ID, b.ID) // ULIDs sort chronologically
+		})
+
+		// Keep the window resolvable for replies and actions; the cache keeps it as a range of its own,
+		// so scrolling back from the present fills the gap up to it
+		for _, msg := range messages {
+			app.References.Set(msg)
+		}
+		app.Messages.Insert(channelID, messages)
+
+		app.GoDo(func() {
+			if app.CurrentChannelID != channelID {
+				return
+			}
+
+			app.setViewingHistory(true)
+			app.renderMessages(messages, func() {
+				if _, w := app.findMessageWidget(messageID); w != nil {
+					app.scrollToMessage(w)
+				}
+			})
+		}, false)
+	}()
+}
+
+// scrollToMessage centres a rendered message in the view and highlights it.
+func (app *ChatApp) scrollToMessage(w *widgets.MessageWidget) {
+	viewHeight := app.messageScroll.Size().Height
+	contentHeight := app.messageListContainer.MinSize().Height
+
+	offset := w.Position().Y - (viewHeight-w.Size().Height)/2
+	offset = max(0, min(offset, contentHeight-viewHeight))
+
+	app.messageScroll.Offset.Y = offset
+	app.messageScroll.Refresh()
+	w.Highlight()
+}
+
+// jumpToPresent leaves a history window and shows live messages again.
+func (app *ChatApp) jumpToPresent() {
+	channelID := app.CurrentChannelID
+	if channelID == "" {
+		return
+	}
+
+	if cached := app.Messages.Latest(channelID); len(cached) > 0 && app.Messages.IsLive(channelID) {
+		app.displayMessages(cached)
+		return
+	}
+
+	app.setViewingHistory(false)
+	app.showLoadingMessages()
+	app.loadChannelMessages(channelID, false)
+}
+
+// setViewingHistory toggles between a history window and live messages.
+// While viewing history, new messages only go to the cache and the "Jump to present" bar is shown.
+func (app *ChatApp) setViewingHistory(viewing bool) {
+	app.viewingHistory = viewing
+	app.refreshOutboxUI()
+
+	if app.jumpToPresentBar == nil {
+		return
+	}
+
+	if viewing {
+		app.jumpToPresentBar.Show()
+	} else {
+		app.jumpToPresentBar.Hide()
+	}
+}
+
+// buildJumpToPresentBar creates the bar shown above the input while viewing older messages.
+func (app *ChatApp) buildJumpToPresentBar() *fyne.Container {
+	bg := canvas.NewRectangle(theme.Colors.SwiftActionBg)
+	bg.CornerRadius = 8
+
+	hintLabel := canvas.NewText("You are viewing older messages", theme.Colors.TimestampText)
+	hintLabel.TextSize = 12
+
+	jumpLabel := canvas.NewText("Jump to present", theme.Colors.TextPrimary)
+	jumpLabel.TextSize = 12
+	jumpLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	jumpBtn := widgets.NewTappableContainer(container.NewPadded(jumpLabel), app.jumpToPresent)
+
+	row := container.NewBorder(nil, nil,
+		widgets.HBoxNoSpacing(widgets.HorizontalSpacer(12), container.NewCenter(hintLabel)),
+		jumpBtn,
+	)
+
+	bar := container.NewPadded(container.NewStack(bg, row))
+	bar.Hide()
+	return bar
+}
@@ -0,0 +1,290 @@
+package app
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sentinelb51/revoltgo"
+
+	"RGOClient/internal/ui/widgets"
+	"RGOClient/internal/util"
+)
+
+// Delay used to coalesce bursts of member and presence events into one member list rebuild.
+const memberListRefreshDelay = 300 * time.Millisecond
+
+// ToggleMemberList shows or hides the member list panel.
+func (app *ChatApp) ToggleMemberList() {
+	app.memberListVisible = !app.memberListVisible
+	app.syncMemberListPanel()
+}
+
+// syncMemberListPanel shows the panel if it is enabled and a server is selected, then refreshes it.
+func (app *ChatApp) syncMemberListPanel() {
+	if app.memberListPanel == nil {
+		return
+	}
+
+	if app.memberListVisible && app.CurrentServer() != nil {
+		app.memberListPanel.Show()
+		app.refreshMemberList()
+	} else {
+		app.memberListPanel.Hide()
+	}
+}
+
+// refreshMemberList regroups the members of the current server in the background and shows them.
+// The full member list is fetched once per server; until then, members already known from state are shown.
+func (app *ChatApp) refreshMemberList() {
+	if app.memberList == nil || !app.memberListVisible || app.Session == nil {
+		return
+	}
+
+	serverID := app.CurrentServerID
+	if serverID == "" {
+		app.memberEntries = nil
+		app.memberList.SetRows(nil)
+		return
+	}
+
+	// State is read here: revoltgo updates it from event goroutines, so only its accessors are safe to use
+	app.memberEntries = app.serverMembers(serverID)
+	app.filterMemberList()
+	app.fetchMembers(serverID)
+}
+
+// filterMemberList regroups the members last read from state with the current filter, in the background.
+func (app *ChatApp) filterMemberList() {
+	if app.memberList == nil || app.CurrentServerID == "" {
+		return
+	}
+
+	serverID := app.CurrentServerID
+	members := app.memberEntries
+	filter := app.memberList.Filter()
+	go func() {
+		rows := groupMemberRows(members, filter)
+		app.GoDo(func() {
+			if app.CurrentServerID == serverID && app.memberList.Filter() == filter {
+				app.memberList.SetRows(rows)
+			}
+		}, false)
+	}()
+}
+
+// scheduleMemberListRefresh coalesces member list refreshes caused by gateway events.
+func (app *ChatApp) scheduleMemberListRefresh() {
+	if !app.memberListVisible || app.memberRefreshTimer != nil {
+		return
+	}
+
+	app.memberRefreshTimer = time.AfterFunc(memberListRefreshDelay, func() {
+		app.GoDo(func() {
+			app.memberRefreshTimer = nil
+			app.refreshMemberList()
+		}, false)
+	})
+}
+
+// fetchMembers fetches all members of a server once.
+// The API has no pagination; the response is grouped off the UI thread and rendered lazily by the list,
+// which only creates widgets for the rows on screen.
+func (app *ChatApp) fetchMembers(serverID string) {
+	if app.membersFetched[serverID] {
+		return
+	}
+	app.membersFetched[serverID] = true
+
+	go func() {
+		if _, err := app.Session.ServerMembers(serverID); err != nil {
+			fmt.Printf("Failed to fetch members of %s: %v\n", serverID, err)
+			app.GoDo(func() {
+				delete(app.membersFetched, serverID) // Retry on the next refresh
+			}, false)
+			return
+		}
+
+		app.GoDo(func() {
+			if app.CurrentServerID == serverID {
+				app.refreshMemberList()
+			}
+		}, false)
+	}()
+}
+
+// memberEntry is a member of a server as read from state, ready to be filtered and grouped.
+type memberEntry struct {
+	row       widgets.MemberRow
+	username  string
+	hoistID   string // Highest hoisted role, if any
+	hoistName string
+	hoistRank int64
+}
+
+// serverMembers reads a server's members, with the roles that decide their group and name colour.
+func (app *ChatApp) serverMembers(serverID string) []memberEntry {
+	state := app.Session.State
+	if state.Server(serverID) == nil {
+		return nil
+	}
+
+	members := state.Members(serverID)
+	entries := make([]memberEntry, 0, len(members))
+	for _, member := range members {
+		user := state.User(member.ID.User)
+		if user == nil {
+			continue
+		}
+
+		entry := memberEntry{
+			row: widgets.MemberRow{
+				UserID:    user.ID,
+				Name:      user.Username,
+				AvatarURL: user.AvatarURL("64"),
+				Online:    user.Online,
+			},
+			username: user.Username,
+		}
+		row := &entry.row
+		if user.DisplayName != nil && *user.DisplayName != "" {
+			row.Name = *user.DisplayName
+		}
+		if member.Nickname != nil && *member.Nickname != "" {
+			row.Name = *member.Nickname
+		}
+		if user.Status != nil {
+			row.Presence = user.Status.Presence
+		}
+
+		// Highest (lowest rank) hoisted role, and highest coloured role for the name
+		var colourRank int64
+		for _, roleID := range member.Roles {
+			role := state.Role(serverID, roleID)
+			if role == nil {
+				continue
+			}
+
+			if role.Hoist && (entry.hoistID == "" || role.Rank < entry.hoistRank) {
+				entry.hoistID, entry.hoistName, entry.hoistRank = roleID, role.Name, role.Rank
+			}
+
+			if role.Colour != nil && (row.NameColour == nil || role.Rank < colourRank) {
+				if colour, ok := util.ParseHexColour(*role.Colour); ok {
+					row.NameColour, colourRank = colour, role.Rank
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// groupMemberRows filters and groups members for the member list.
+// Online members are grouped by their highest hoisted role, then the rest under "Online"; offline members come last.
+// Safe to call from any goroutine.
+func groupMemberRows(members []memberEntry, filter string) []widgets.MemberRow {
+	type group struct {
+		name string
+		rank int64
+		rows []widgets.MemberRow
+	}
+
+	hoisted := make(map[string]*group) // roleID → group
+	online := &group{name: "Online"}
+	offline := &group{name: "Offline"}
+
+	filter = strings.ToLower(filter)
+	for _, member := range members {
+		row := member.row
+		if filter != "" && !strings.Contains(strings.ToLower(row.Name), filter) &&
+			!strings.Contains(strings.ToLower(member.username), filter) {
+			continue
+		}
+
+		isOnline := row.Online && row.Presence != revoltgo.UserStatusPresenceInvisible
+		switch {
+		case !isOnline:
+			offline.rows = append(offline.rows, row)
+		case member.hoistID != "":
+			g := hoisted[member.hoistID]
+			if g == nil {
+				g = &group{name: member.hoistName, rank: member.hoistRank}
+				hoisted[member.hoistID] = g
+			}
+			g.rows = append(g.rows, row)
+		default:
+			online.rows = append(online.rows, row)
+		}
+	}
+
+	groups := make([]*group, 0, len(hoisted)+2)
+	for _, g := range hoisted {
+		groups = append(groups, g)
+	}
+	slices.SortFunc(groups, func(a, b *group) int {
+		return cmp.Compare(a.rank, b.rank)
+	})
+	groups = append(groups, online, offline)
+
+	var rows []widgets.MemberRow
+	for _, g := range groups {
+		if len(g.rows) == 0 {
+			continue
+		}
+
+		slices.SortFunc(g.rows, func(a, b widgets.MemberRow) int {
+			return cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		})
+
+		rows = append(rows, widgets.MemberRow{Header: fmt.Sprintf("%s — %d", strings.ToUpper(g.name), len(g.rows))})
+		rows = append(rows, g.rows...)
+	}
+	return rows
+}
+
+// onServerMemberJoin refreshes the member list when someone joins the current server.
+func (app *ChatApp) onServerMemberJoin(_ *revoltgo.Session, event *revoltgo.EventServerMemberJoin) {
+	app.onServerMembersChanged(event.ID)
+}
+
+// onServerMemberLeave refreshes the member list when someone leaves the current server.
+func (app *ChatApp) onServerMemberLeave(_ *revoltgo.Session, event *revoltgo.EventServerMemberLeave) {
+	app.onServerMembersChanged(event.ID)
+}
+
+// onServerMemberUpdate refreshes the member list when a member's nickname or roles change.
+func (app *ChatApp) onServerMemberUpdate(_ *revoltgo.Session, event *revoltgo.EventServerMemberUpdate) {
+	app.onServerMembersChanged(event.ID.Server)
+}
+
+// onUserUpdate refreshes the member list on presence and profile changes of members of the current server,
+// and the Home list and friends panel on changes of DM recipients and friends.
+func (app *ChatApp) onUserUpdate(_ *revoltgo.Session, event *revoltgo.EventUserUpdate) {
+	userID := event.ID
+	app.GoDo(func() {
+		if app.CurrentServerID == "" {
+			if app.isRecipient(userID) {
+				app.RefreshChannelList()
+			}
+			if _, ok := app.relationships[userID]; ok {
+				app.refreshFriends()
+			}
+			return
+		}
+		if app.Session.State.Member(userID, app.CurrentServerID) != nil {
+			app.scheduleMemberListRefresh()
+		}
+	}, false)
+}
+
+// onServerMembersChanged schedules a member list refresh if the server is the current one.
+func (app *ChatApp) onServerMembersChanged(serverID string) {
+	app.GoDo(func() {
+		if serverID == app.CurrentServerID {
+			app.scheduleMemberListRefresh()
+		}
+	}, false)
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"RGOClient/internal/ui/widgets/input"
+)
+
+// mentionCandidates returns users in the current channel matching query, for mention autocomplete.
+// Recent speakers come first, then prefix matches before substring matches, then alphabetical.
+func (app *ChatApp) mentionCandidates(query string) []input.MentionCandidate {
+	if app.Session == nil {
+		return nil
+	}
+
+	channel := app.CurrentChannel()
+	if channel == nil {
+		return nil
+	}
+
+	// Members for server channels, recipients for DMs and groups
+	var userIDs []string
+	if channel.Server != nil {
+		for _, member := range app.Session.State.Members(*channel.Server) {
+			userIDs = append(userIDs, member.ID.User)
+		}
+	} else {
+		userIDs = channel.Recipients
+	}
+
+	// Rank by how recently each user spoke in this channel (0 = most recent)
+	recent := make(map[string]int)
+	messages := app.Messages.Get(channel.ID)
+	for i := len(messages) - 1; i >= 0; i-- {
+		if _, seen := recent[messages[i].Author]; !seen {
+			recent[messages[i].Author] = len(recent)
+		}
+	}
+
+	type ranked struct {
+		candidate input.MentionCandidate
+		recent    int
+		prefix    bool
+	}
+
+	query = strings.ToLower(query)
+	var matches []ranked
+	for _, id := range userIDs {
+		user := app.Session.State.User(id)
+		if user == nil {
+			continue
+		}
+
+		name := strings.ToLower(user.Username)
+		if !strings.Contains(name, query) {
+			continue
+		}
+
+		rank, ok := recent[id]
+		if !ok {
+			rank = len(recent)
+		}
+
+		matches = append(matches, ranked{
+			candidate: input.MentionCandidate{ID: id, Name: user.Username, AvatarURL: user.AvatarURL("64")},
+			recent:    rank,
+			prefix:    strings.HasPrefix(name, query),
+		})
+	}
+
+	slices.SortFunc(matches, func(a, b ranked) int {
+		if a.recent != b.recent {
+			return cmp.Compare(a.recent, b.recent)
+		}
+		if a.prefix != b.prefix {
+			if a.prefix {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(strings.ToLower(a.candidate.Name), strings.ToLower(b.candidate.Name))
+	})
+
+	candidates := make([]input.MentionCandidate, len(matches))
+	for i, match := range matches {
+		candidates[i] = match.candidate
+	}
+	return candidates
+}
@@ -2,14 +2,16 @@ package app
 
 import (
 	"RGOClient/internal/ui/widgets/input"
+	"errors"
 	"fmt"
 	"image"
 	"net/url"
-	"os"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
 	"github.com/sentinelb51/revoltgo"
 
@@ -21,6 +23,9 @@ import (
 // Message rendering batch size for responsive UI.
 const messageBatchSize = 100
 
+// Number of older messages fetched per history page.
+const historyPageSize = 50
+
 // showCenteredStatus displays a centered status message in the message area.
 func (app *ChatApp) showCenteredStatus(text string) {
 	app.messageListContainer.Objects = nil
@@ -48,11 +53,22 @@ func (app *ChatApp) showLoadingMessages() {
 }
 
 // loadChannelMessages fetches messages from API in background.
-func (app *ChatApp) loadChannelMessages(channelID string) {
+// With loadStored, the channel's stored history is read from disk and shown first.
+func (app *ChatApp) loadChannelMessages(channelID string, loadStored bool) {
 	// Reset depleted state on load attempt
 	app.Messages.SetDepleted(channelID, false)
 
 	go func() {
+		if loadStored {
+			if stored := app.Messages.LoadStored(channelID); len(stored) > 0 {
+				app.GoDo(func() {
+					if app.CurrentChannelID == channelID {
+						app.displayMessages(stored)
+					}
+				}, true)
+			}
+		}
+
 		if app.Session == nil {
 			return
 		}
@@ -65,7 +81,8 @@ func (app *ChatApp) loadChannelMessages(channelID string) {
 
 		if err != nil {
 			app.GoDo(func() {
-				if app.CurrentChannelID == channelID {
+				// Stored history stays on screen when offline
+				if app.CurrentChannelID == channelID && len(app.Messages.Latest(channelID)) == 0 {
 					app.showErrorMessage("Failed to load messages")
 				}
 			}, true)
@@ -82,12 +99,12 @@ func (app *ChatApp) loadChannelMessages(channelID string) {
 			return
 		}
 
-		// Store directly - cache maintains newest→oldest order
+		// The cache stores oldest→newest and joins the page to cached messages it reaches
 		app.Messages.Set(channelID, messages.Messages)
 
 		app.GoDo(func() {
 			if app.CurrentChannelID == channelID {
-				app.displayMessages(messages.Messages)
+				app.displayMessages(app.Messages.Latest(channelID))
 			}
 		}, true)
 	}()
@@ -98,12 +115,21 @@ func (app *ChatApp) showErrorMessage(msg string) {
 	app.showCenteredStatus(msg)
 }
 
-// displayMessages renders messages using batched rendering.
-// Messages are stored oldest→newest, iterate forward.
+// displayMessages shows the live messages of the current channel and scrolls to the newest.
 func (app *ChatApp) displayMessages(messages []*revoltgo.Message) {
+	app.setViewingHistory(false)
+	app.renderMessages(messages, app.scrollToBottom)
+}
+
+// renderMessages renders messages using batched rendering, then calls onDone.
+// Messages are stored oldest→newest, iterate forward.
+func (app *ChatApp) renderMessages(messages []*revoltgo.Message, onDone func()) {
 	app.messageListContainer.Objects = nil
 	channelID := app.CurrentChannelID
 
+	// Images of the new messages start downloading now; those only the replaced ones wanted are dropped
+	app.replaceImageScope(messages)
+
 	go func() {
 		// Iterate forward: oldest→newest (chronological order)
 		for i := 0; i < len(messages); i += messageBatchSize {
@@ -130,7 +156,8 @@ func (app *ChatApp) displayMessages(messages []*revoltgo.Message) {
 
 		app.GoDo(func() {
 			if app.CurrentChannelID == channelID {
-				app.scrollToBottom()
+				onDone()
+				app.prioritizeVisibleImages()
 			}
 		}, false)
 	}()
@@ -152,7 +179,8 @@ func (app *ChatApp) scrollToBottom() {
 
 // AddMessage adds a new message to the current channel.
 func (app *ChatApp) AddMessage(msg *revoltgo.Message) {
-	if app.CurrentChannelID == "" {
+	// Live messages are already cached; they are shown after jumping to the present
+	if app.CurrentChannelID == "" || app.viewingHistory {
 		return
 	}
 
@@ -196,6 +224,7 @@ func (app *ChatApp) AddMessage(msg *revoltgo.Message) {
 		// If we adjusted offset manually
 		app.messageScroll.Refresh()
 	}
+	app.prioritizeVisibleImages()
 }
 
 // showImageViewerAttachment displays an image attachment in a popup window.
@@ -263,73 +292,173 @@ func (app *ChatApp) handleMessageSubmit(text string, msgInput *input.MessageInpu
 	if (text == "" && len(msgInput.Attachments) == 0) || app.CurrentChannelID == "" || app.Session == nil {
 		return
 	}
+	if app.outbox == nil {
+		dialog.ShowError(errors.New("cannot send yet: still connecting"), app.window)
+		return
+	}
+
+	// Attachments upload as they are attached; any that failed blocks the whole message
+	files := make([]OutboxAttachment, len(msgInput.Attachments))
+	var failed []string
+	for i, att := range msgInput.Attachments {
+		if app.checkAttachmentSize(msgInput, att) && att.Err == nil {
+			files[i] = OutboxAttachment{Path: att.Path, Name: att.Name, ID: att.ID}
+			continue
+		}
+		failed = append(failed, fmt.Sprintf("%s: %v", att.Name, att.Err))
+	}
+	if len(failed) > 0 {
+		dialog.ShowError(fmt.Errorf("remove or retry the files that cannot be sent:\n%s", strings.Join(failed, "\n")), app.window)
+		return
+	}
+	for _, att := range msgInput.Attachments {
+		if att.Uploading() {
+			msgInput.SubmitWhenUploaded()
+			return
+		}
+	}
 
 	// Capture necessary data to avoid race conditions with UI clearing
 	channelID := app.CurrentChannelID
-	// Create a copy of attachments as we'll clear the widget immediately
-	attachments := make([]input.Attachment, len(msgInput.Attachments))
-	copy(attachments, msgInput.Attachments)
 
-	// Copy replies
-	replies := make([]input.Reply, len(msgInput.Replies))
-	copy(replies, msgInput.Replies)
+	replies := make([]*revoltgo.MessageReplies, len(msgInput.Replies))
+	for i, r := range msgInput.Replies {
+		replies[i] = &revoltgo.MessageReplies{
+			ID:      r.ID,
+			Mention: r.Mention,
+		}
+	}
 
 	// Clear UI immediately for responsiveness
 	msgInput.SetText("")
 	msgInput.ClearAttachments()
 	msgInput.ClearReplies()
 
-	// Perform network operations in background
-	go func() {
-		attachmentIDs := make([]string, 0, len(attachments))
+	// Shown at once as a local message; the outbox sends it in the background
+	app.queueMessage(channelID, text, files, replies)
+}
 
-		for _, att := range attachments {
-			f, err := os.Open(att.Path)
-			if err != nil {
-				fmt.Printf("Failed to open attachment %s: %v\n", att.Path, err)
-				continue
-			}
+// handleMessageEdit sends an edit for one of our messages and updates it in place.
+func (app *ChatApp) handleMessageEdit(messageID, text string, msgInput *input.MessageInput) {
+	if app.CurrentChannelID == "" || app.Session == nil {
+		return
+	}
 
-			payload := &revoltgo.File{
-				Name:   att.Name,
-				Reader: f,
-			}
+	channelID := app.CurrentChannelID
+	original := app.CachedMessage(channelID, messageID)
+
+	// Nothing to do if the content is unchanged or empty
+	if text == "" || (original != nil && original.Content == text) {
+		msgInput.CancelEdit()
+		return
+	}
 
-			uploaded, err := app.Session.AttachmentUpload(payload)
-			_ = f.Close()
+	// Edit mode and the text are kept until the server accepts the edit, so a failed edit is not lost
+	go func() {
+		edited, err := app.Session.ChannelMessageEdit(channelID, messageID, revoltgo.MessageEditData{
+			Content: text,
+		})
 
-			if err != nil {
-				fmt.Printf("Failed to upload attachment %s: %v\n", att.Name, err)
-				continue
-			}
+		if err != nil {
+			fmt.Printf("Failed to edit message: %v\n", err)
+			app.GoDo(func() {
+				dialog.ShowError(fmt.Errorf("failed to edit message: %w", err), app.window)
+			}, false)
+			return
+		}
 
-			attachmentIDs = append(attachmentIDs, uploaded.ID)
+		if edited != nil && edited.ID != "" {
+			app.Messages.Update(channelID, edited)
 		}
 
-		msgReplies := make([]*revoltgo.MessageReplies, len(replies))
-		for i, r := range replies {
-			msgReplies[i] = &revoltgo.MessageReplies{
-				ID:      r.ID,
-				Mention: r.Mention,
+		app.GoDo(func() {
+			if msgInput.EditingID() == messageID {
+				msgInput.CancelEdit()
 			}
-		}
+			if edited != nil && edited.ID != "" && app.CurrentChannelID == channelID {
+				app.updateMessageWidget(edited)
+			}
+		}, false)
+	}()
+}
 
-		send := revoltgo.MessageSend{
-			Content:     text,
-			Attachments: attachmentIDs,
-			Replies:     msgReplies,
+// findMessageWidget returns the index and widget rendering the given message ID.
+// Returns -1 and nil if the message is not rendered.
+func (app *ChatApp) findMessageWidget(messageID string) (int, *widgets.MessageWidget) {
+	for i, obj := range app.messageListContainer.Objects {
+		if w, ok := obj.(*widgets.MessageWidget); ok && w.Message.ID == messageID {
+			return i, w
 		}
+	}
+	return -1, nil
+}
 
-		if _, err := app.Session.ChannelMessageSend(channelID, send); err != nil {
-			fmt.Printf("Failed to send message: %v\n", err)
+// updateMessageWidget re-renders a visible message in place.
+func (app *ChatApp) updateMessageWidget(msg *revoltgo.Message) {
+	index, _ := app.findMessageWidget(msg.ID)
+	if index < 0 {
+		return
+	}
+
+	w := widgets.NewMessageWidget(msg, app)
+	if w == nil {
+		return
+	}
+
+	app.messageListContainer.Objects[index] = w
+	app.messageListContainer.Refresh()
+}
+
+// deleteMessage deletes a message through the API and removes it locally.
+func (app *ChatApp) deleteMessage(channelID, messageID string) {
+	if app.Session == nil {
+		return
+	}
+
+	go func() {
+		if err := app.Session.ChannelMessageDelete(channelID, messageID); err != nil {
+			fmt.Printf("Failed to delete message: %v\n", err)
 			return
 		}
+
+		app.GoDo(func() {
+			app.removeMessage(channelID, messageID)
+		}, false)
 	}()
 }
 
+// removeMessage drops a message from the cache and the visible list without a full rebuild.
+// Reply previews referencing the message are re-rendered to show it was deleted.
+func (app *ChatApp) removeMessage(channelID, messageID string) {
+	app.Messages.Remove(channelID, messageID)
+	app.References.Remove(messageID)
+
+	if app.CurrentChannelID != channelID {
+		return
+	}
+
+	if app.messageInput != nil {
+		if app.messageInput.EditingID() == messageID {
+			app.messageInput.CancelEdit()
+		}
+		app.messageInput.RemoveReply(messageID)
+	}
+
+	if index, _ := app.findMessageWidget(messageID); index >= 0 {
+		objects := app.messageListContainer.Objects
+		app.messageListContainer.Objects = append(objects[:index:index], objects[index+1:]...)
+	}
+
+	app.messageListContainer.Refresh()
+
+	// Re-render messages that reply to the deleted one
+	app.refreshReplyPreviews(messageID)
+}
+
 // loadMoreHistory fetches older messages when scrolling up.
 func (app *ChatApp) loadMoreHistory() {
-	if app.isLoadingHistory || app.CurrentChannelID == "" || app.Messages.IsDepleted(app.CurrentChannelID) {
+	// History windows are not the newest cached range, so there is nothing to extend
+	if app.isLoadingHistory || app.viewingHistory || app.CurrentChannelID == "" || app.Messages.IsDepleted(app.CurrentChannelID) {
 		return
 	}
 
@@ -346,8 +475,10 @@ func (app *ChatApp) loadMoreHistory() {
 			}, true)
 		}()
 
-		// Get oldest loaded message ID
-		msgs := app.Messages.Get(app.CurrentChannelID)
+		channelID := app.CurrentChannelID
+
+		// Get oldest shown message ID; older cached ranges are behind a gap
+		msgs := app.Messages.Latest(channelID)
 		if len(msgs) == 0 {
 			// Should not happen as this is loadMoreHistory.
 			// But if it does, it's just a no-op or error
@@ -357,32 +488,37 @@ func (app *ChatApp) loadMoreHistory() {
 
 		// Fetch older messages
 		// API returns newest->oldest
-		history, err := app.Session.ChannelMessages(app.CurrentChannelID, revoltgo.ChannelMessagesParams{
+		history, err := app.Session.ChannelMessages(channelID, revoltgo.ChannelMessagesParams{
 			Before:       oldestID,
-			Limit:        50,
+			Limit:        historyPageSize,
 			IncludeUsers: true,
 		})
 
-		if err != nil || len(history.Messages) == 0 {
-			if len(history.Messages) == 0 {
-				app.Messages.SetDepleted(app.CurrentChannelID, true)
-			}
-			// fmt.Println("No more history or error:", err)
+		if err != nil {
+			fmt.Printf("Failed to load history of %s: %v\n", channelID, err)
 			return
 		}
 
-		// Update cache
-		app.Messages.Prepend(app.CurrentChannelID, history.Messages)
+		// Update cache; a page reaching an older range brings its messages along
+		fetched := len(history.Messages)
+		older := app.Messages.Prepend(channelID, oldestID, history.Messages)
+
+		// A short page means the beginning of the channel was reached
+		if fetched < historyPageSize {
+			app.Messages.SetDepleted(channelID, true)
+		}
 
 		// Update UI
 		app.GoDo(func() {
 			// No loader to remove
-			app.prependMessagesToUI(history.Messages)
+			if app.CurrentChannelID == channelID && !app.viewingHistory {
+				app.prependMessagesToUI(older)
+			}
 		}, true)
 	}()
 }
 
-// prependMessagesToUI adds older messages to top of list and maintains scroll position.
+// prependMessagesToUI adds older messages (oldest→newest) to top of list and maintains scroll position.
 func (app *ChatApp) prependMessagesToUI(messages []*revoltgo.Message) {
 	if len(messages) == 0 {
 		return
@@ -391,10 +527,10 @@ func (app *ChatApp) prependMessagesToUI(messages []*revoltgo.Message) {
 	// Capture current content height
 	oldHeight := app.messageListContainer.MinSize().Height
 
-	// Convert to widgets (Chronological: reverse API response)
-	var newWidgets []fyne.CanvasObject
-	for i := len(messages) - 1; i >= 0; i-- {
-		w := widgets.NewMessageWidget(messages[i], app)
+	// Convert to widgets (Chronological)
+	newWidgets := make([]fyne.CanvasObject, 0, len(messages))
+	for _, msg := range messages {
+		w := widgets.NewMessageWidget(msg, app)
 		newWidgets = append(newWidgets, w)
 	}
 
@@ -411,4 +547,5 @@ func (app *ChatApp) prependMessagesToUI(messages []*revoltgo.Message) {
 		app.messageScroll.Offset.Y += diff
 		app.messageScroll.Refresh()
 	}
+	app.prioritizeVisibleImages()
 }
@@ -0,0 +1,609 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/sentinelb51/revoltgo"
+
+	"RGOClient/internal/ui/widgets"
+)
+
+// Outbox retry timing.
+const (
+	outboxMaxAttempts = 5 // Failed sends are retried this many times before waiting for the user
+	outboxBaseDelay   = 2 * time.Second
+	outboxMaxDelay    = time.Minute
+)
+
+// sentEchoTimeout is how long a delivered message waits for its gateway echo before the server's copy is shown.
+const sentEchoTimeout = 10 * time.Second
+
+// outboxFileName is the name of the queue file in an account's outbox directory.
+const outboxFileName = "outbox.json"
+
+// OutboxEntry is a message waiting to be sent.
+type OutboxEntry struct {
+	Nonce       string                     `json:"nonce"` // Sent with the message; the API rejects a repeat, so retries never send twice
+	ChannelID   string                     `json:"channel_id"`
+	Content     string                     `json:"content,omitempty"`
+	Attachments []OutboxAttachment         `json:"attachments,omitempty"`
+	Replies     []*revoltgo.MessageReplies `json:"replies,omitempty"`
+	Attempts    int                        `json:"attempts,omitempty"`
+	Failed      bool                       `json:"failed,omitempty"` // Gave up retrying; waits for Retry or Discard
+	Error       string                     `json:"error,omitempty"`  // Last send error
+}
+
+// OutboxAttachment is a file of an outbox entry.
+type OutboxAttachment struct {
+	Path string `json:"path"` // Copy in the outbox directory, so the file can be sent after the original moves
+	Name string `json:"name"`
+	ID   string `json:"id,omitempty"` // Uploaded file ID; retries do not upload it again
+}
+
+// Outbox is the persistent queue of an account's unsent messages.
+// Every change is written to disk, so queued messages survive a crash or restart.
+type Outbox struct {
+	mutex   sync.Mutex
+	dir     string // Empty for an outbox kept in memory only
+	entries []OutboxEntry
+	wake    chan struct{}
+}
+
+// getOutboxDir returns the directory holding an account's outbox.
+func getOutboxDir(accountID string) string {
+	root := filepath.Join(".", "config")
+	if configDirectory, err := os.UserConfigDir(); err == nil {
+		root = filepath.Join(configDirectory, "RGOClient")
+	} else if homeDirectory, err := os.UserHomeDir(); err == nil {
+		root = filepath.Join(homeDirectory, ".config", "RGOClient")
+	}
+	return filepath.Join(root, "outbox", accountID)
+}
+
+// OpenOutbox loads the outbox of an account.
+func OpenOutbox(accountID string) (*Outbox, error) {
+	outbox := &Outbox{
+		dir:  getOutboxDir(accountID),
+		wake: make(chan struct{}, 1),
+	}
+	if err := os.MkdirAll(outbox.dir, 0700); err != nil {
+		return nil, fmt.Errorf("create outbox directory: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outbox.dir, outboxFileName))
+	if os.IsNotExist(err) {
+		return outbox, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &outbox.entries); err != nil {
+		return nil, fmt.Errorf("decode outbox: %w", err)
+	}
+	return outbox, nil
+}
+
+// newMemoryOutbox creates an outbox that is not saved, for when the account's outbox cannot be opened.
+func newMemoryOutbox() *Outbox {
+	return &Outbox{wake: make(chan struct{}, 1)}
+}
+
+// NewEntry creates an entry with a fresh nonce. Attachments refer to the original files
+// until CopyAttachments copies them into the outbox.
+func (outbox *Outbox) NewEntry(channelID, content string, files []OutboxAttachment, replies []*revoltgo.MessageReplies) OutboxEntry {
+	return OutboxEntry{
+		Nonce:       ulid.Make().String(),
+		ChannelID:   channelID,
+		Content:     content,
+		Attachments: slices.Clone(files),
+		Replies:     replies,
+	}
+}
+
+// CopyAttachments copies a queued entry's files into the outbox, so it can be sent after the originals move.
+// It may take a moment for large files. A file that cannot be copied is sent from its original path.
+func (outbox *Outbox) CopyAttachments(entry OutboxEntry) {
+	if outbox.dir == "" {
+		return
+	}
+
+	for i, attachment := range entry.Attachments {
+		if attachment.ID != "" {
+			continue // Uploaded already
+		}
+		copied, err := outbox.copyAttachment(entry.Nonce, i, attachment.Path, attachment.Name)
+		if err != nil {
+			log.Printf("Failed to copy attachment %s into the outbox: %v\n", attachment.Path, err)
+			continue
+		}
+		outbox.update(entry.Nonce, func(e *OutboxEntry) {
+			if i < len(e.Attachments) {
+				e.Attachments = slices.Clone(e.Attachments)
+				e.Attachments[i].Path = copied
+			}
+		})
+	}
+
+	// Sent or discarded while copying; the copies are not needed
+	if !outbox.Contains(entry.Nonce) {
+		_ = os.RemoveAll(filepath.Join(outbox.dir, entry.Nonce))
+	}
+}
+
+// copyAttachment copies a file into the entry's directory and returns the copy's path.
+// Each file gets a directory named after its index, so files with the same name keep apart.
+func (outbox *Outbox) copyAttachment(nonce string, index int, path, name string) (string, error) {
+	dir := filepath.Join(outbox.dir, nonce, strconv.Itoa(index))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	source, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer source.Close()
+
+	target := filepath.Join(dir, filepath.Base(name))
+	destination, err := os.Create(target)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = io.Copy(destination, source)
+	if closeErr := destination.Close(); err == nil {
+		err = closeErr
+	}
+	return target, err
+}
+
+// Add queues an entry and wakes the sender.
+func (outbox *Outbox) Add(entry OutboxEntry) {
+	outbox.mutex.Lock()
+	outbox.entries = append(outbox.entries, entry)
+	outbox.save()
+	outbox.mutex.Unlock()
+
+	outbox.Wake()
+}
+
+// Remove drops an entry and its copied files, after it was sent or discarded.
+// Returns false if the entry was already removed.
+func (outbox *Outbox) Remove(nonce string) bool {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+
+	count := len(outbox.entries)
+	outbox.entries = slices.DeleteFunc(outbox.entries, func(e OutboxEntry) bool {
+		return e.Nonce == nonce
+	})
+	if len(outbox.entries) == count {
+		return false
+	}
+
+	outbox.save()
+	if outbox.dir != "" {
+		_ = os.RemoveAll(filepath.Join(outbox.dir, nonce))
+	}
+	return true
+}
+
+// Contains reports whether an entry is still queued.
+func (outbox *Outbox) Contains(nonce string) bool {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+
+	return slices.ContainsFunc(outbox.entries, func(e OutboxEntry) bool {
+		return e.Nonce == nonce
+	})
+}
+
+// Retry requeues a failed entry and wakes the sender.
+func (outbox *Outbox) Retry(nonce string) {
+	outbox.update(nonce, func(e *OutboxEntry) {
+		e.Failed = false
+		e.Attempts = 0
+		e.Error = ""
+	})
+	outbox.Wake()
+}
+
+// Fail records a failed attempt. After outboxMaxAttempts, or at once if the server rejected the message,
+// the entry is marked failed and no longer retried. Returns the updated entry.
+func (outbox *Outbox) Fail(nonce string, err error) OutboxEntry {
+	return outbox.update(nonce, func(e *OutboxEntry) {
+		e.Attempts++
+		e.Error = err.Error()
+		e.Failed = e.Attempts >= outboxMaxAttempts || isSendRejected(err)
+	})
+}
+
+// isSendRejected reports whether the server rejected a message or one of its files (a 4xx status other
+// than a timeout or rate limit), so retrying the same request cannot succeed.
+func isSendRejected(err error) bool {
+	if isUploadRejected(err) {
+		return true
+	}
+
+	// revoltgo and uploadFile report statuses only in the error text
+	text := err.Error()
+	index := strings.Index(text, "bad status code ")
+	if index < 0 {
+		return false
+	}
+	var status int
+	if _, scanErr := fmt.Sscanf(text[index:], "bad status code %d", &status); scanErr != nil {
+		return false
+	}
+	return status >= 400 && status < 500 && status != http.StatusRequestTimeout && status != http.StatusTooManyRequests
+}
+
+// SetAttachmentID records that an entry's attachment was uploaded.
+func (outbox *Outbox) SetAttachmentID(nonce string, index int, id string) {
+	outbox.update(nonce, func(e *OutboxEntry) {
+		if index < len(e.Attachments) {
+			e.Attachments = slices.Clone(e.Attachments)
+			e.Attachments[index].ID = id
+		}
+	})
+}
+
+// update applies a change to an entry and saves the outbox. Returns the updated entry.
+func (outbox *Outbox) update(nonce string, apply func(*OutboxEntry)) OutboxEntry {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+
+	for i := range outbox.entries {
+		if outbox.entries[i].Nonce == nonce {
+			apply(&outbox.entries[i])
+			outbox.save()
+			return outbox.entries[i]
+		}
+	}
+	return OutboxEntry{}
+}
+
+// Next returns the oldest entry that is not failed, if any.
+func (outbox *Outbox) Next() (OutboxEntry, bool) {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+
+	for _, entry := range outbox.entries {
+		if !entry.Failed {
+			return entry, true
+		}
+	}
+	return OutboxEntry{}, false
+}
+
+// Entries returns the queued entries of a channel, oldest first.
+func (outbox *Outbox) Entries(channelID string) []OutboxEntry {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+
+	var entries []OutboxEntry
+	for _, entry := range outbox.entries {
+		if entry.ChannelID == channelID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Wake makes the sender try the next entry now instead of after its backoff.
+func (outbox *Outbox) Wake() {
+	select {
+	case outbox.wake <- struct{}{}:
+	default:
+	}
+}
+
+// save writes the queue to disk, replacing the file atomically. Call with the mutex held.
+func (outbox *Outbox) save() {
+	if outbox.dir == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(outbox.entries, "", "  ")
+	if err != nil {
+		log.Printf("Failed to encode outbox: %v\n", err)
+		return
+	}
+
+	temp, err := os.CreateTemp(outbox.dir, outboxFileName+".*.tmp")
+	if err != nil {
+		log.Printf("Failed to save outbox: %v\n", err)
+		return
+	}
+
+	_, err = temp.Write(data)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), filepath.Join(outbox.dir, outboxFileName))
+	}
+	if err != nil {
+		_ = os.Remove(temp.Name())
+		log.Printf("Failed to save outbox: %v\n", err)
+	}
+}
+
+// openOutbox loads the account's outbox and starts sending what is queued.
+func (app *ChatApp) openOutbox(accountID string) {
+	outbox, err := OpenOutbox(accountID)
+	if err != nil {
+		log.Printf("Failed to open outbox, unsent messages are kept in memory only: %v\n", err)
+		outbox = newMemoryOutbox()
+	}
+
+	app.outbox = outbox
+	app.stopOutbox = make(chan struct{})
+	go app.runOutbox(app.Session, outbox, app.stopOutbox)
+}
+
+// closeOutbox stops sending. Queued entries stay on disk for the next session.
+func (app *ChatApp) closeOutbox() {
+	if app.stopOutbox != nil {
+		close(app.stopOutbox)
+		app.stopOutbox = nil
+	}
+	app.outbox = nil
+	app.sentEntries = nil
+	clear(app.uploadProgress)
+}
+
+// runOutbox sends queued entries in order, retrying failed sends with exponential backoff.
+func (app *ChatApp) runOutbox(session *revoltgo.Session, outbox *Outbox, stop <-chan struct{}) {
+	for {
+		entry, ok := outbox.Next()
+		if !ok {
+			select {
+			case <-stop:
+				return
+			case <-outbox.wake:
+				continue
+			}
+		}
+
+		message, err := app.sendOutboxEntry(session, outbox, entry)
+		if err == nil {
+			// The gateway echo may have arrived first and replaced the entry already
+			if outbox.Remove(entry.Nonce) {
+				app.GoDo(func() { app.onOutboxSent(entry, message) }, false)
+			}
+			continue
+		}
+
+		log.Printf("Failed to send message (attempt %d): %v\n", entry.Attempts+1, err)
+		entry = outbox.Fail(entry.Nonce, err)
+		app.GoDo(func() {
+			delete(app.uploadProgress, entry.Nonce)
+			app.refreshOutboxUI()
+		}, false)
+		if entry.Nonce == "" || entry.Failed {
+			continue // Discarded meanwhile, or waiting for the user
+		}
+
+		delay := min(outboxBaseDelay<<(entry.Attempts-1), outboxMaxDelay)
+		select {
+		case <-stop:
+			return
+		case <-outbox.wake:
+		case <-time.After(delay):
+		}
+	}
+}
+
+// sendOutboxEntry uploads an entry's remaining attachments and sends the message.
+// Returns the created message, or nil if an earlier attempt already created it.
+func (app *ChatApp) sendOutboxEntry(session *revoltgo.Session, outbox *Outbox, entry OutboxEntry) (*revoltgo.Message, error) {
+	attachmentIDs := make([]string, 0, len(entry.Attachments))
+	for i, attachment := range entry.Attachments {
+		if attachment.ID == "" {
+			id, err := uploadFile(context.Background(), session, attachment.Path, attachment.Name, app.reportUploadProgress(entry, i))
+			if err != nil {
+				return nil, fmt.Errorf("upload %s: %w", attachment.Name, err)
+			}
+			attachment.ID = id
+			outbox.SetAttachmentID(entry.Nonce, i, id)
+		}
+		attachmentIDs = append(attachmentIDs, attachment.ID)
+	}
+
+	// revoltgo's MessageSend has no nonce, so the request is made directly
+	body := struct {
+		revoltgo.MessageSend
+		Nonce string `json:"nonce"`
+	}{
+		MessageSend: revoltgo.MessageSend{
+			Content:     entry.Content,
+			Attachments: attachmentIDs,
+			Replies:     entry.Replies,
+		},
+		Nonce: entry.Nonce,
+	}
+
+	var message *revoltgo.Message
+	err := session.HTTP.Request(http.MethodPost, revoltgo.EndpointChannelMessages(entry.ChannelID), body, &message)
+	if err != nil && strings.Contains(err.Error(), "DuplicateNonce") {
+		return nil, nil // An earlier attempt went through, but its response was lost
+	}
+	return message, err
+}
+
+// reportUploadProgress returns an upload callback showing the progress of an entry's attachment
+// on its pending message.
+func (app *ChatApp) reportUploadProgress(entry OutboxEntry, index int) func(sent, total int64) {
+	count := float64(len(entry.Attachments))
+	return onPercent(func(fraction float64) {
+		fraction = (float64(index) + fraction) / count
+		app.GoDo(func() {
+			app.uploadProgress[entry.Nonce] = fraction
+			if w := app.pendingMessages[entry.Nonce]; w != nil {
+				w.SetProgress(fraction)
+			}
+		}, false)
+	})
+}
+
+// onOutboxSent keeps a delivered message on screen until the gateway echoes it (see reconcileSent).
+// Without an echo, e.g. while reconnecting, the server's copy is shown after sentEchoTimeout.
+func (app *ChatApp) onOutboxSent(entry OutboxEntry, message *revoltgo.Message) {
+	delete(app.uploadProgress, entry.Nonce)
+
+	if message != nil && !app.Messages.Contains(entry.ChannelID, message.ID) {
+		app.sentEntries = append(app.sentEntries, entry)
+		time.AfterFunc(sentEchoTimeout, func() {
+			app.GoDo(func() {
+				if !app.reconcileSent(entry.Nonce) || !app.Messages.Append(entry.ChannelID, message) {
+					return
+				}
+				if app.CurrentChannelID == entry.ChannelID {
+					app.AddMessage(message)
+				}
+			}, false)
+		})
+	}
+
+	app.refreshOutboxUI()
+}
+
+// reconcileSent drops the local copy of a message once the server's copy is shown in its place.
+// Returns false if there is no local copy.
+func (app *ChatApp) reconcileSent(nonce string) bool {
+	if nonce == "" {
+		return false
+	}
+
+	count := len(app.sentEntries)
+	app.sentEntries = slices.DeleteFunc(app.sentEntries, func(e OutboxEntry) bool {
+		return e.Nonce == nonce
+	})
+	removed := len(app.sentEntries) < count
+
+	// Echoed before the send request returned
+	if app.outbox != nil && app.outbox.Remove(nonce) {
+		removed = true
+	}
+
+	if removed {
+		delete(app.uploadProgress, nonce)
+		app.refreshOutboxUI()
+	}
+	return removed
+}
+
+// refreshOutboxUI shows the current channel's unconfirmed messages below its messages.
+func (app *ChatApp) refreshOutboxUI() {
+	if app.outboxContainer == nil {
+		return
+	}
+
+	app.outboxContainer.Objects = nil
+	clear(app.pendingMessages)
+	if app.CurrentChannelID != "" && !app.viewingHistory {
+		for _, entry := range app.sentEntries {
+			if entry.ChannelID == app.CurrentChannelID {
+				app.addPendingMessage(entry, true)
+			}
+		}
+		if app.outbox != nil {
+			for _, entry := range app.outbox.Entries(app.CurrentChannelID) {
+				app.addPendingMessage(entry, false)
+			}
+		}
+	}
+	app.outboxContainer.Refresh()
+}
+
+// addPendingMessage shows an entry in the outbox container.
+func (app *ChatApp) addPendingMessage(entry OutboxEntry, sent bool) {
+	w := widgets.NewPendingMessage(app.pendingMessageInfo(entry, sent), app.retryOutboxEntry, app.discardOutboxEntry)
+	if fraction, ok := app.uploadProgress[entry.Nonce]; ok {
+		w.SetProgress(fraction)
+	}
+	app.pendingMessages[entry.Nonce] = w
+	app.outboxContainer.Add(w)
+}
+
+// pendingMessageInfo describes an outbox entry for its widget, as a local message by us.
+func (app *ChatApp) pendingMessageInfo(entry OutboxEntry, sent bool) widgets.PendingMessageInfo {
+	message := &revoltgo.Message{
+		ID:      entry.Nonce, // A ULID too, so it has a timestamp
+		Nonce:   entry.Nonce,
+		Channel: entry.ChannelID,
+		Content: entry.Content,
+	}
+	if app.Session != nil && app.Session.State != nil {
+		if self := app.Session.State.Self(); self != nil {
+			message.Author = self.ID
+		}
+	}
+	for _, reply := range entry.Replies {
+		message.Replies = append(message.Replies, reply.ID)
+	}
+
+	info := widgets.PendingMessageInfo{
+		Message: message,
+		Failed:  entry.Failed,
+		Error:   entry.Error,
+		Sent:    sent,
+	}
+	for _, attachment := range entry.Attachments {
+		info.Attachments = append(info.Attachments, attachment.Name)
+	}
+	return info
+}
+
+// retryOutboxEntry requeues a failed message.
+func (app *ChatApp) retryOutboxEntry(nonce string) {
+	if app.outbox == nil {
+		return
+	}
+	app.outbox.Retry(nonce)
+	app.refreshOutboxUI()
+}
+
+// discardOutboxEntry drops a failed message without sending it.
+func (app *ChatApp) discardOutboxEntry(nonce string) {
+	if app.outbox == nil {
+		return
+	}
+	app.outbox.Remove(nonce)
+	app.refreshOutboxUI()
+}
+
+// queueMessage adds a message to the outbox and shows it right away as a local message.
+// Attachments are copied into the outbox in the background.
+func (app *ChatApp) queueMessage(channelID, content string, files []OutboxAttachment, replies []*revoltgo.MessageReplies) {
+	outbox := app.outbox
+	if outbox == nil {
+		log.Println("Failed to send message: outbox is not open")
+		return
+	}
+
+	entry := outbox.NewEntry(channelID, content, files, replies)
+	outbox.Add(entry)
+	app.refreshOutboxUI()
+	if app.CurrentChannelID == channelID {
+		app.scrollToBottom()
+	}
+
+	if len(entry.Attachments) > 0 {
+		go outbox.CopyAttachments(entry)
+	}
+}
@@ -0,0 +1,211 @@
+package app
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"fyne.io/fyne/v2/widget"
+	"github.com/sentinelb51/revoltgo"
+
+	"RGOClient/internal/cache"
+	"RGOClient/internal/ui/widgets"
+	"RGOClient/internal/util"
+)
+
+// OnAvatarTapped opens the profile card of a user (avatars, author names and mentions).
+func (app *ChatApp) OnAvatarTapped(userID string) {
+	if app.Session == nil || userID == "" {
+		return
+	}
+
+	profile := app.Profiles.Get(userID)
+
+	var popup *widget.PopUp
+	var card *widgets.ProfileCard
+	card = widgets.NewProfileCard(app.profileInfo(userID, profile), func() {
+		popup.Hide()
+		app.openDirectMessage(userID)
+	}, func() {
+		app.addFriend(userID, card)
+	})
+	popup = widgets.ShowProfileCard(app.window.Canvas(), card)
+
+	if profile != nil {
+		return
+	}
+
+	// Fetch the rest of the profile lazily; the card fills in when it arrives
+	go func() {
+		profile, err := app.Profiles.Load(userID, func() (*cache.Profile, error) {
+			return app.fetchProfile(userID)
+		})
+
+		if err != nil {
+			fmt.Printf("Failed to fetch profile of %s: %v\n", userID, err)
+			profile = &cache.Profile{} // Stop showing the loading state
+		}
+
+		app.GoDo(func() {
+			card.SetInfo(app.profileInfo(userID, profile))
+		}, false)
+	}()
+}
+
+// fetchProfile fetches the bio, banner and mutual servers of a user.
+func (app *ChatApp) fetchProfile(userID string) (*cache.Profile, error) {
+	profile := &cache.Profile{}
+
+	if app.Session.State.User(userID) == nil {
+		user, err := app.Session.User(userID)
+		if err != nil {
+			return nil, err
+		}
+		profile.User = user
+	}
+
+	// Profiles may be hidden from us; show what we have instead of failing
+	if data, err := app.Session.UserProfile(userID); err == nil && data != nil {
+		profile.Bio = data.Content
+		profile.Background = data.Background
+	}
+
+	if self := app.Session.State.Self(); self == nil || self.ID != userID {
+		// revoltgo's UserMutual decodes into a slice, but the API returns a single object
+		var mutual revoltgo.MutualFriendsAndServersResponse
+		endpoint := revoltgo.EndpointUserMutual(userID)
+		if err := app.Session.HTTP.Request(http.MethodGet, endpoint, nil, &mutual); err == nil {
+			profile.MutualServers = mutual.Servers
+		}
+	}
+
+	return profile, nil
+}
+
+// profileInfo combines live state (names, presence, roles) with a fetched profile, which may be nil.
+func (app *ChatApp) profileInfo(userID string, profile *cache.Profile) widgets.ProfileInfo {
+	info := widgets.ProfileInfo{
+		UserID:      userID,
+		DisplayName: "Unknown user",
+		Loading:     profile == nil,
+	}
+
+	if self := app.Session.State.Self(); self != nil {
+		info.IsSelf = self.ID == userID
+	}
+
+	user := app.Session.State.User(userID)
+	if user == nil && profile != nil {
+		user = profile.User
+	}
+
+	if user != nil {
+		info.AvatarURL = user.AvatarURL("256")
+		info.DisplayName = user.Username
+		if user.DisplayName != nil && *user.DisplayName != "" {
+			info.DisplayName = *user.DisplayName
+		}
+		info.Username = util.FullUsername(user.Username, user.Discriminator)
+		info.Online = user.Online
+		info.Badges = util.BadgeNames(user.Badges)
+		info.Relationship = app.relationship(userID)
+
+		if user.Status != nil {
+			info.Presence = user.Status.Presence
+			info.StatusText = user.Status.Text
+		}
+	}
+
+	if server := app.CurrentServer(); server != nil {
+		if member := app.Session.State.Member(userID, server.ID); member != nil {
+			if member.Nickname != nil && *member.Nickname != "" {
+				info.DisplayName = *member.Nickname
+			}
+			info.Roles = memberRoles(server, member)
+		}
+	}
+
+	if profile != nil {
+		info.Bio = profile.Bio
+		if profile.Background != nil {
+			info.BannerURL = profile.Background.URL("")
+		}
+
+		for _, serverID := range profile.MutualServers {
+			if server := app.Session.State.Server(serverID); server != nil {
+				info.MutualServers = append(info.MutualServers, server.Name)
+			}
+		}
+	}
+
+	return info
+}
+
+// memberRoles returns a member's roles in rank order (lowest rank is the most important).
+func memberRoles(server *revoltgo.Server, member *revoltgo.ServerMember) []widgets.ProfileRole {
+	type rankedRole struct {
+		role widgets.ProfileRole
+		rank int64
+	}
+
+	var ranked []rankedRole
+	for _, roleID := range member.Roles {
+		role := server.Roles[roleID]
+		if role == nil {
+			continue
+		}
+
+		entry := rankedRole{role: widgets.ProfileRole{Name: role.Name}, rank: role.Rank}
+		if role.Colour != nil {
+			if colour, ok := util.ParseHexColour(*role.Colour); ok {
+				entry.role.Colour = colour
+			}
+		}
+		ranked = append(ranked, entry)
+	}
+
+	slices.SortFunc(ranked, func(a, b rankedRole) int {
+		return cmp.Compare(a.rank, b.rank)
+	})
+
+	roles := make([]widgets.ProfileRole, len(ranked))
+	for i, r := range ranked {
+		roles[i] = r.role
+	}
+	return roles
+}
+
+// addFriend sends (or accepts) a friend request and updates the card with the new relationship.
+func (app *ChatApp) addFriend(userID string, card *widgets.ProfileCard) {
+	go func() {
+		user, err := app.Session.FriendAdd(userID)
+		if err != nil {
+			fmt.Printf("Failed to add friend %s: %v\n", userID, err)
+			return
+		}
+
+		app.GoDo(func() {
+			if user != nil {
+				app.setRelationship(user)
+			}
+			card.SetInfo(app.profileInfo(userID, app.Profiles.Get(userID)))
+		}, false)
+	}()
+}
+
+// openDirectMessage opens (creating if needed) the DM channel with a user and switches to it.
+func (app *ChatApp) openDirectMessage(userID string) {
+	go func() {
+		channel, err := app.Session.DirectMessageCreate(userID)
+		if err != nil || channel == nil {
+			fmt.Printf("Failed to open direct message with %s: %v\n", userID, err)
+			return
+		}
+
+		app.GoDo(func() {
+			app.addPrivateChannel(channel)
+			app.OnChannelTapped(channel.ID)
+		}, false)
+	}()
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/sentinelb51/revoltgo"
+
+	"RGOClient/internal/util"
+)
+
+// OnReact toggles our reaction on a message.
+func (app *ChatApp) OnReact(message *revoltgo.Message, emojiID string) {
+	if app.Session == nil {
+		return
+	}
+
+	self := app.Session.State.Self()
+	if self == nil {
+		return
+	}
+
+	// Use the cached copy; the widget's message may be outdated
+	if cached := app.CachedMessage(message.Channel, message.ID); cached != nil {
+		message = cached
+	}
+
+	channelID, messageID, userID := message.Channel, message.ID, self.ID
+	reacted := slices.Contains(message.Reactions[emojiID], userID)
+
+	if !reacted && !util.CanReact(message, emojiID) {
+		return
+	}
+
+	go func() {
+		var err error
+		if reacted {
+			err = app.Session.ChannelMessageReactionDelete(channelID, messageID, emojiID)
+		} else {
+			err = app.Session.ChannelMessageReactionCreate(channelID, messageID, emojiID)
+		}
+
+		if err != nil {
+			fmt.Printf("Failed to toggle reaction: %v\n", err)
+			return
+		}
+
+		// Apply locally; the gateway echo is ignored as a duplicate
+		app.applyReaction(channelID, messageID, emojiID, userID, !reacted)
+	}()
+}
+
+// onMessageReact handles a user adding a reaction.
+func (app *ChatApp) onMessageReact(_ *revoltgo.Session, event *revoltgo.EventMessageReact) {
+	app.applyReaction(event.ChannelID, event.ID, event.EmojiID, event.UserID, true)
+}
+
+// onMessageUnreact handles a user removing a reaction.
+func (app *ChatApp) onMessageUnreact(_ *revoltgo.Session, event *revoltgo.EventMessageUnreact) {
+	app.applyReaction(event.ChannelID, event.ID, event.EmojiID, event.UserID, false)
+}
+
+// applyReaction updates the cached message and re-renders it if visible.
+func (app *ChatApp) applyReaction(channelID, messageID, emojiID, userID string, add bool) {
+	var msg *revoltgo.Message
+	if add {
+		msg = app.Messages.AddReaction(channelID, messageID, emojiID, userID)
+	} else {
+		msg = app.Messages.RemoveReaction(channelID, messageID, emojiID, userID)
+	}
+
+	if msg == nil {
+		return
+	}
+
+	app.GoDo(func() {
+		if channelID == app.CurrentChannelID {
+			app.updateMessageWidget(msg)
+		}
+	}, false)
+}
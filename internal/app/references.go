@@ -0,0 +1,57 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+
+	"RGOClient/internal/ui/widgets"
+)
+
+// fetchReference fetches a referenced message that is not cached, then re-renders its reply previews.
+// Concurrent requests for the same message share one fetch.
+func (app *ChatApp) fetchReference(channelID, messageID string) {
+	if app.Session == nil || !app.References.Begin(messageID) {
+		return
+	}
+
+	go func() {
+		msg, err := app.Session.ChannelMessage(channelID, messageID)
+		if err != nil || msg == nil {
+			fmt.Printf("Failed to fetch referenced message %s: %v\n", messageID, err)
+			app.References.Fail(messageID)
+			return
+		}
+
+		app.References.Set(msg)
+
+		app.GoDo(func() {
+			if app.CurrentChannelID == channelID {
+				app.refreshReplyPreviews(messageID)
+			}
+		}, false)
+	}()
+}
+
+// refreshReplyPreviews re-renders visible messages and reply cards that reference a message.
+func (app *ChatApp) refreshReplyPreviews(messageID string) {
+	changed := false
+	for i, obj := range app.messageListContainer.Objects {
+		w, ok := obj.(*widgets.MessageWidget)
+		if !ok || !slices.Contains(w.Message.Replies, messageID) {
+			continue
+		}
+
+		if replacement := widgets.NewMessageWidget(w.Message, app); replacement != nil {
+			app.messageListContainer.Objects[i] = replacement
+			changed = true
+		}
+	}
+
+	if changed {
+		app.messageListContainer.Refresh()
+	}
+
+	if app.messageInput != nil && app.messageInput.HasReply(messageID) {
+		app.messageInput.RefreshReplies()
+	}
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sentinelb51/revoltgo"
+)
+
+// typingExpiry removes a typing entry if no stop event arrives in time.
+const typingExpiry = 6 * time.Second
+
+// onChannelStartTyping records that a user started typing in a channel.
+func (app *ChatApp) onChannelStartTyping(_ *revoltgo.Session, event *revoltgo.EventChannelStartTyping) {
+	channelID, userID := event.ID, event.User
+	app.GoDo(func() {
+		app.setTyping(channelID, userID, true)
+	}, false)
+}
+
+// onChannelStopTyping clears a user's typing state in a channel.
+func (app *ChatApp) onChannelStopTyping(_ *revoltgo.Session, event *revoltgo.EventChannelStopTyping) {
+	channelID, userID := event.ID, event.User
+	app.GoDo(func() {
+		app.setTyping(channelID, userID, false)
+	}, false)
+}
+
+// setTyping adds or removes a typing entry. Must be called on the UI thread.
+// Entries expire after typingExpiry unless refreshed by another start event.
+func (app *ChatApp) setTyping(channelID, userID string, typing bool) {
+	if app.Session == nil {
+		return
+	}
+
+	if self := app.Session.State.Self(); self != nil && self.ID == userID {
+		return
+	}
+
+	users := app.typingUsers[channelID]
+	if timer, ok := users[userID]; ok {
+		timer.Stop()
+		delete(users, userID)
+	}
+
+	if typing {
+		if users == nil {
+			users = make(map[string]*time.Timer)
+			app.typingUsers[channelID] = users
+		}
+
+		var timer *time.Timer
+		timer = time.AfterFunc(typingExpiry, func() {
+			app.GoDo(func() {
+				// Ignore if the entry was refreshed or removed in the meantime
+				if app.typingUsers[channelID][userID] == timer {
+					app.setTyping(channelID, userID, false)
+				}
+			}, false)
+		})
+		users[userID] = timer
+	} else if len(users) == 0 {
+		delete(app.typingUsers, channelID)
+	}
+
+	if channelID == app.CurrentChannelID {
+		app.refreshTypingIndicator()
+	}
+}
+
+// refreshTypingIndicator shows the users typing in the current channel.
+func (app *ChatApp) refreshTypingIndicator() {
+	if app.typingIndicator == nil || app.Session == nil {
+		return
+	}
+
+	var names []string
+	for userID := range app.typingUsers[app.CurrentChannelID] {
+		if user := app.Session.State.User(userID); user != nil {
+			names = append(names, user.Username)
+		}
+	}
+	slices.Sort(names)
+
+	app.typingIndicator.SetNames(names)
+}
+
+// sendTyping notifies the server that we started or stopped typing in the current channel.
+func (app *ChatApp) sendTyping(typing bool) {
+	channelID := app.CurrentChannelID
+	if app.Session == nil || channelID == "" {
+		return
+	}
+
+	go func() {
+		var err error
+		if typing {
+			err = app.Session.ChannelBeginTyping(channelID)
+		} else {
+			err = app.Session.ChannelEndTyping(channelID)
+		}
+
+		if err != nil {
+			fmt.Printf("Failed to send typing state: %v\n", err)
+		}
+	}()
+}
@@ -8,6 +8,7 @@ import (
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/widget"
 
+	"RGOClient/internal/emoji"
 	"RGOClient/internal/ui/theme"
 	"RGOClient/internal/ui/widgets"
 	"RGOClient/internal/ui/widgets/input"
@@ -18,9 +19,12 @@ func (app *ChatApp) buildUI() fyne.CanvasObject {
 	serverList := app.buildServerList()
 	channelList := app.buildChannelList()
 	messageBox := app.buildMessageBox()
+	memberList := app.buildMemberList()
 
-	content := container.NewBorder(nil, nil, channelList, nil, messageBox)
-	return container.NewBorder(nil, nil, serverList, nil, content)
+	app.reconnectBanner = app.buildReconnectBanner()
+
+	content := container.NewBorder(nil, nil, channelList, memberList, messageBox)
+	return container.NewBorder(app.reconnectBanner, nil, serverList, nil, content)
 }
 
 // buildServerList creates the server sidebar component.
@@ -34,10 +38,25 @@ func (app *ChatApp) buildServerList() fyne.CanvasObject {
 	return container.NewStack(bg, scroll)
 }
 
+// buildMemberList creates the member list panel on the right.
+func (app *ChatApp) buildMemberList() fyne.CanvasObject {
+	app.memberList = widgets.NewMemberList(app.OnAvatarTapped, func(string) {
+		app.filterMemberList()
+	})
+
+	app.memberListPanel = container.NewStack(app.memberList)
+	app.syncMemberListPanel()
+	return app.memberListPanel
+}
+
 // RefreshServerList rebuilds the server list UI from current data.
 func (app *ChatApp) RefreshServerList() {
 	app.serverListContainer.Objects = nil
 
+	home := widgets.NewHomeWidget(app.SelectHome)
+	home.SetSelected(app.CurrentServerID == "")
+	app.serverListContainer.Add(container.NewCenter(home))
+
 	for _, serverID := range app.ServerIDs {
 		server := app.Session.State.Server(serverID)
 		if server == nil {
@@ -63,7 +82,7 @@ func (app *ChatApp) buildChannelList() fyne.CanvasObject {
 	bg := canvas.NewRectangle(theme.Colors.ChannelListBackground)
 	bg.SetMinSize(fyne.NewSize(theme.Sizes.ChannelSidebarWidth, 0))
 
-	serverName := "Server"
+	serverName := homeHeader
 	if s := app.CurrentServer(); s != nil {
 		serverName = s.Name
 	}
@@ -89,6 +108,12 @@ func (app *ChatApp) buildChannelList() fyne.CanvasObject {
 func (app *ChatApp) RefreshChannelList() {
 	app.channelListContainer.Objects = nil
 
+	if app.CurrentServerID == "" {
+		app.refreshPrivateChannelList()
+		app.channelListContainer.Refresh()
+		return
+	}
+
 	server := app.CurrentServer()
 	if server == nil {
 		app.channelListContainer.Refresh()
@@ -176,10 +201,13 @@ func (app *ChatApp) createChannelWidget(channelID string) *widgets.ChannelWidget
 func (app *ChatApp) buildMessageBox() fyne.CanvasObject {
 	bg := canvas.NewRectangle(theme.Colors.MessageAreaBackground)
 
-	app.messageScroll = widgets.NewObservableVScroll(app.messageListContainer)
+	// Queued messages stay below the channel's messages, whatever is rendered or prepended above
+	app.outboxContainer = widgets.NewVerticalNoSpacingContainer()
+	app.messageScroll = widgets.NewObservableVScroll(widgets.NewVerticalNoSpacingContainer(app.messageListContainer, app.outboxContainer))
 
 	// Infinite scroll handler
 	app.messageScroll.OnScroll = func(pos fyne.Position) {
+		app.prioritizeVisibleImages()
 		if pos.Y <= 0 && !app.isLoadingHistory {
 			app.loadMoreHistory()
 		}
@@ -194,14 +222,41 @@ func (app *ChatApp) buildMessageBox() fyne.CanvasObject {
 	msgInput.OnSubmit = func(text string) {
 		app.handleMessageSubmit(text, msgInput)
 	}
+	msgInput.OnEdit = func(messageID, text string) {
+		app.handleMessageEdit(messageID, text, msgInput)
+	}
+	msgInput.OnEditLast = app.editLastMessage
+	msgInput.OnTyping = app.sendTyping
+	msgInput.MentionSource = app.mentionCandidates
+	msgInput.EmojiSource = app.emojiCandidates
+	msgInput.OnAttach = func(att *input.Attachment) {
+		app.startAttachmentUpload(msgInput, att)
+	}
 	msgInput.RegisterDropHandler(app.window)
 
+	emojiIcon := canvas.NewImageFromFile("assets/emoji.svg")
+	emojiIcon.FillMode = canvas.ImageFillContain
+	emojiIcon.SetMinSize(fyne.NewSquareSize(theme.Sizes.EmojiButtonSize))
+
+	var emojiBtn *widgets.TappableContainer
+	emojiBtn = widgets.NewTappableContainer(container.NewPadded(emojiIcon), func() {
+		widgets.ShowEmojiPicker(emojiBtn, app.CustomEmoji(), nil, func(e emoji.Emoji) {
+			msgInput.InsertText(e.Text())
+			app.window.Canvas().Focus(msgInput)
+		})
+	})
+
 	inputContainer := container.NewPadded(container.NewVBox(
+		msgInput.EditContainer,
 		msgInput.ReplyContainer,
 		msgInput.AttachmentContainer,
-		msgInput,
+		msgInput.SuggestionContainer,
+		container.NewBorder(nil, nil, nil, emojiBtn, msgInput),
 	))
 
+	app.typingIndicator = widgets.NewTypingIndicator()
+	inputArea := container.NewBorder(nil, app.typingIndicator, nil, nil, inputContainer)
+
 	channelName := "channel"
 	if ch := app.CurrentChannel(); ch != nil {
 		channelName = ch.Name
@@ -210,11 +265,24 @@ func (app *ChatApp) buildMessageBox() fyne.CanvasObject {
 	app.channelHeaderLabel = widget.NewLabelWithStyle(channelName, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
 
 	icon := widgets.GetHashtagIcon()
-	headerContent := container.NewHBox(icon, app.channelHeaderLabel)
+
+	membersIcon := canvas.NewImageFromFile("assets/members.svg")
+	membersIcon.FillMode = canvas.ImageFillContain
+	membersIcon.SetMinSize(fyne.NewSquareSize(theme.Sizes.MemberToggleSize))
+	membersBtn := widgets.NewTappableContainer(container.NewPadded(membersIcon), app.ToggleMemberList)
+
+	headerContent := container.NewBorder(nil, nil, container.NewHBox(icon, app.channelHeaderLabel), membersBtn)
 	header := container.NewPadded(headerContent)
 
-	layout := container.NewBorder(header, inputContainer, nil, nil, app.messageScroll)
-	return container.NewStack(bg, layout)
+	app.jumpToPresentBar = app.buildJumpToPresentBar()
+	messageArea := container.NewStack(app.messageScroll, container.NewBorder(nil, app.jumpToPresentBar, nil, nil))
+
+	app.chatView = container.NewBorder(header, inputArea, nil, nil, messageArea)
+
+	app.friendsPanel = widgets.NewFriendsPanel(app.friendActions())
+	app.friendsPanel.Hide()
+
+	return container.NewStack(bg, app.chatView, app.friendsPanel)
 }
 
 // newSpacer creates a transparent rectangle with the given minimum size.
@@ -238,9 +306,16 @@ func (app *ChatApp) updateServerSelectionUI(selectedID string) {
 // syncChannelListUI updates visual state of all channel widgets.
 func (app *ChatApp) syncChannelListUI() {
 	updateWidget := func(obj fyne.CanvasObject) {
-		if w, ok := obj.(*widgets.ChannelWidget); ok {
+		switch w := obj.(type) {
+		case *widgets.ChannelWidget:
 			id := w.Channel.ID
 			w.SetState(id == app.CurrentChannelID, app.UnreadChannels[id])
+		case *widgets.DirectMessageWidget:
+			if id := w.Info.ChannelID; id != "" {
+				w.SetState(id == app.CurrentChannelID, app.UnreadChannels[id])
+			} else {
+				w.SetState(app.friendsVisible, app.incomingRequests() > 0) // Friends entry
+			}
 		}
 	}
 
@@ -0,0 +1,259 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+
+	"github.com/sentinelb51/revoltgo"
+
+	"RGOClient/internal/ui/widgets"
+	"RGOClient/internal/ui/widgets/input"
+)
+
+// attachmentTag is the Autumn tag message attachments are uploaded to.
+const attachmentTag = "attachments"
+
+// Upload rejections: retrying the same upload cannot succeed.
+var (
+	errFileTooLarge    = errors.New("file is too large")
+	errUploadForbidden = errors.New("not allowed to upload files")
+)
+
+// uploadClient makes attachment uploads. It has no timeout, since large files may take minutes;
+// revoltgo's client gives up after 10 seconds.
+var uploadClient = &http.Client{}
+
+// progressReader counts the bytes read through it.
+type progressReader struct {
+	reader   io.Reader
+	sent     int64
+	total    int64
+	progress func(sent, total int64)
+}
+
+// Read reads from the underlying reader and reports the bytes read so far.
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.sent += int64(n)
+		if r.progress != nil {
+			r.progress(r.sent, r.total)
+		}
+	}
+	return n, err
+}
+
+// onPercent returns an upload progress callback that calls report whenever the uploaded percentage changes.
+func onPercent(report func(fraction float64)) func(sent, total int64) {
+	percent := -1
+	return func(sent, total int64) {
+		fraction := float64(sent) / float64(max(total, 1))
+		if p := int(fraction * 100); p != percent {
+			percent = p
+			report(fraction)
+		}
+	}
+}
+
+// uploadFile uploads a file to the attachments tag and returns its ID, reporting the bytes sent as it goes.
+// revoltgo's AttachmentUpload buffers the whole body and reports nothing, so the request is made directly.
+// Cancelling ctx aborts the upload.
+func uploadFile(ctx context.Context, session *revoltgo.Session, path, name string, progress func(sent, total int64)) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	// The multipart framing around the file is written up front, so the body's length is known
+	var framing bytes.Buffer
+	form := multipart.NewWriter(&framing)
+	if _, err := form.CreateFormFile("file", name); err != nil {
+		return "", err
+	}
+	headerLength := framing.Len()
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	body := io.MultiReader(
+		bytes.NewReader(framing.Bytes()[:headerLength]),
+		&progressReader{reader: f, total: info.Size(), progress: progress},
+		bytes.NewReader(framing.Bytes()[headerLength:]),
+	)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, revoltgo.EndpointAutumn(attachmentTag), body)
+	if err != nil {
+		return "", err
+	}
+	request.ContentLength = int64(framing.Len()) + info.Size()
+	request.Header.Set("Content-Type", form.FormDataContentType())
+	request.Header.Set("User-Agent", session.HTTP.Header("User-Agent"))
+	if session.Selfbot() {
+		request.Header.Set("X-Session-Token", session.Token)
+	} else {
+		request.Header.Set("X-Bot-Token", session.Token)
+	}
+
+	response, err := uploadClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusRequestEntityTooLarge:
+		return "", errFileTooLarge
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return "", errUploadForbidden
+	}
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusCreated {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return "", fmt.Errorf("bad status code %d: %s", response.StatusCode, message)
+	}
+
+	var uploaded revoltgo.FileAttachment
+	if err := json.NewDecoder(response.Body).Decode(&uploaded); err != nil {
+		return "", fmt.Errorf("decode upload response: %w", err)
+	}
+	return uploaded.ID, nil
+}
+
+// fetchUploadLimits returns the instance's maximum file size per Autumn tag.
+func fetchUploadLimits(session *revoltgo.Session) (map[string]int64, error) {
+	var root revoltgo.RootData
+	if err := session.HTTP.Request(http.MethodGet, revoltgo.BaseURL(), nil, &root); err != nil {
+		return nil, err
+	}
+
+	autumnURL := root.Features.Autumn.URL
+	if autumnURL == "" {
+		autumnURL = revoltgo.CDNURL()
+	}
+
+	response, err := uploadClient.Get(autumnURL)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bad status code %d", response.StatusCode)
+	}
+
+	var config struct {
+		Tags map[string]struct {
+			MaxSize int64 `json:"max_size"`
+		} `json:"tags"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&config); err != nil {
+		return nil, fmt.Errorf("decode autumn config: %w", err)
+	}
+
+	limits := make(map[string]int64, len(config.Tags))
+	for tag, settings := range config.Tags {
+		if settings.MaxSize > 0 {
+			limits[tag] = settings.MaxSize
+		}
+	}
+	return limits, nil
+}
+
+// loadUploadLimits fetches the instance's file size limits in the background.
+// Until they arrive, files are not checked before uploading.
+func (app *ChatApp) loadUploadLimits(session *revoltgo.Session) {
+	go func() {
+		limits, err := fetchUploadLimits(session)
+		if err != nil {
+			log.Printf("Failed to fetch upload limits: %v\n", err)
+			return
+		}
+		app.GoDo(func() {
+			app.uploadLimits = limits
+
+			// Files attached before the limits were known
+			if app.messageInput != nil {
+				for _, att := range app.messageInput.Attachments {
+					app.checkAttachmentSize(app.messageInput, att)
+				}
+			}
+		}, false)
+	}()
+}
+
+// checkAttachmentSize marks an attachment over the instance's limit as unsendable, cancelling its upload.
+// Returns false if it is over the limit. Until the limits are fetched, every file passes.
+func (app *ChatApp) checkAttachmentSize(msgInput *input.MessageInput, att *input.Attachment) bool {
+	limit, ok := app.uploadLimits[attachmentTag]
+	if !ok || att.Size <= limit {
+		return true
+	}
+	if att.Err == nil {
+		if att.Cancel != nil {
+			att.Cancel()
+		}
+		msgInput.SetAttachmentError(att, fmt.Errorf("%w (the limit is %s)", errFileTooLarge, widgets.FormatFileSize(int(limit))))
+	}
+	return false
+}
+
+// isUploadRejected reports whether an upload failed because of the file or the account, rather than
+// the connection, so the outbox should not retry it automatically.
+func isUploadRejected(err error) bool {
+	return errors.Is(err, errFileTooLarge) || errors.Is(err, errUploadForbidden)
+}
+
+// startAttachmentUpload checks a newly attached file against the instance's limit and uploads it
+// in the background, showing progress on its card. Removing the file cancels the upload.
+// A failed upload blocks sending until the file is removed or its retry succeeds.
+func (app *ChatApp) startAttachmentUpload(msgInput *input.MessageInput, att *input.Attachment) {
+	if !app.checkAttachmentSize(msgInput, att) {
+		return
+	}
+
+	session := app.Session
+	if session == nil {
+		msgInput.SetAttachmentError(att, errors.New("not logged in"))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	att.Cancel = cancel
+
+	go func() {
+		id, err := uploadFile(ctx, session, att.Path, att.Name, onPercent(func(fraction float64) {
+			app.GoDo(func() {
+				msgInput.SetAttachmentProgress(att, fraction)
+			}, false)
+		}))
+		cancelled := ctx.Err() != nil
+		cancel()
+
+		app.GoDo(func() {
+			switch {
+			case cancelled:
+				// The file was removed
+			case isUploadRejected(err):
+				msgInput.SetAttachmentError(att, err)
+			case err != nil:
+				log.Printf("Failed to upload %s: %v\n", att.Name, err)
+				msgInput.SetAttachmentError(att, fmt.Errorf("upload failed, remove or retry the file: %w", err))
+			default:
+				msgInput.SetAttachmentUploaded(att, id)
+			}
+		}, false)
+	}()
+}
@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Cache file extensions. An entry is the image as downloaded plus a metadata record.
+const (
+	imageDataExtension   = ".img"
+	imageMetaExtension   = ".json"
+	legacyImageExtension = ".png" // Entries of older versions: decoded images re-encoded as PNG
+)
+
+// imageMeta describes a cached image file.
+type imageMeta struct {
+	ContentType string    `json:"content_type"`
+	URL         string    `json:"url,omitempty"` // Unknown for migrated entries
+	Size        int64     `json:"size"`
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+// imageEntry is a downloaded image waiting to be written to disk.
+type imageEntry struct {
+	data []byte
+	meta imageMeta
+}
+
+// dataPath returns the path of an image's original bytes.
+func (cache *ImageCache) dataPath(imageID string) string {
+	return filepath.Join(cache.cacheDir, imageID+imageDataExtension)
+}
+
+// metaPath returns the path of an image's metadata record.
+func (cache *ImageCache) metaPath(imageID string) string {
+	return filepath.Join(cache.cacheDir, imageID+imageMetaExtension)
+}
+
+// writeEntry writes an image's bytes, then its metadata, and returns their total size.
+// Each file replaces the previous one atomically, so a crash leaves either the old file or the new one,
+// never a partial one.
+func (cache *ImageCache) writeEntry(imageID string, entry imageEntry) (int64, error) {
+	meta, err := json.Marshal(entry.meta)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := writeFileAtomic(cache.dataPath(imageID), entry.data); err != nil {
+		return 0, err
+	}
+	if err := writeFileAtomic(cache.metaPath(imageID), meta); err != nil {
+		return 0, err
+	}
+	return int64(len(entry.data) + len(meta)), nil
+}
+
+// readData returns an image's original bytes. Entries of an older version are read as they are;
+// the save goroutine migrates them, so reads never write to the cache directory.
+func (cache *ImageCache) readData(imageID string) ([]byte, error) {
+	data, err := os.ReadFile(cache.dataPath(imageID))
+	if !os.IsNotExist(err) {
+		return data, err
+	}
+
+	legacy, legacyErr := os.ReadFile(cache.legacyPath(imageID))
+	if legacyErr == nil {
+		return legacy, nil
+	}
+	// migrateLegacyEntries may have just moved it
+	return os.ReadFile(cache.dataPath(imageID))
+}
+
+// legacyPath returns the path of an image stored by an older version.
+func (cache *ImageCache) legacyPath(imageID string) string {
+	return filepath.Join(cache.cacheDir, imageID+legacyImageExtension)
+}
+
+// migrateLegacy turns a PNG written by an older version into an entry: the PNG bytes become the
+// original bytes. Returns false if there is no such file. Call only from the save goroutine.
+func (cache *ImageCache) migrateLegacy(imageID string) bool {
+	legacyPath := cache.legacyPath(imageID)
+	info, err := os.Stat(legacyPath)
+	if err != nil {
+		return false
+	}
+
+	meta, err := json.Marshal(imageMeta{
+		ContentType: "image/png",
+		Size:        info.Size(),
+		FetchedAt:   info.ModTime(),
+	})
+	if err != nil {
+		return false
+	}
+
+	// The metadata is written first: an interrupted migration leaves the PNG in place to retry
+	if err := writeFileAtomic(cache.metaPath(imageID), meta); err != nil {
+		return false
+	}
+	if err := os.Rename(legacyPath, cache.dataPath(imageID)); err != nil {
+		return false
+	}
+	cache.recordWrite(imageID, info.Size()+int64(len(meta)))
+	return true
+}
+
+// migrateLegacyEntries migrates every PNG left by an older version. Runs on the save goroutine before
+// the index is loaded; until then readData reads the PNGs directly.
+func (cache *ImageCache) migrateLegacyEntries() {
+	var imageIDs []string
+	_ = filepath.WalkDir(cache.cacheDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, legacyImageExtension) {
+			return nil
+		}
+		if relative, err := filepath.Rel(cache.cacheDir, path); err == nil {
+			imageIDs = append(imageIDs, strings.TrimSuffix(filepath.ToSlash(relative), legacyImageExtension))
+		}
+		return nil
+	})
+
+	for _, imageID := range imageIDs {
+		cache.migrateLegacy(imageID)
+	}
+}
+
+// writeFileAtomic writes a file through a temporary file in the same directory.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = temp.Write(data)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(temp.Name())
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testImage returns a small image with a distinct corner pixel.
+func testImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	return img
+}
+
+func TestKeepsOriginalBytes(t *testing.T) {
+	cache := newImageCache(t.TempDir())
+
+	var original bytes.Buffer
+	if err := jpeg.Encode(&original, testImage(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Set("photo", "https://example.com/photo", "", original.Bytes()); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	cache.FlushToDisk()
+
+	data, err := os.ReadFile(cache.dataPath("photo"))
+	if err != nil {
+		t.Fatalf("entry not written: %v", err)
+	}
+	if !bytes.Equal(data, original.Bytes()) {
+		t.Fatal("stored bytes differ from the downloaded ones")
+	}
+
+	cache.ClearMemoryCache()
+	if cache.Get("photo") == nil {
+		t.Fatal("Get() did not decode the stored entry")
+	}
+}
+
+func TestMigratesLegacyPNG(t *testing.T) {
+	dir := t.TempDir()
+	cache := newImageCache(dir)
+
+	var legacy bytes.Buffer
+	if err := png.Encode(&legacy, testImage()); err != nil {
+		t.Fatal(err)
+	}
+	legacyPath := filepath.Join(dir, "avatar"+legacyImageExtension)
+	if err := os.WriteFile(legacyPath, legacy.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Readable before it is migrated, without being written
+	img := cache.Get("avatar")
+	if img == nil {
+		t.Fatal("legacy entry not readable")
+	}
+	if r, _, _, _ := img.At(0, 0).RGBA(); r>>8 != 255 {
+		t.Fatal("legacy entry decoded wrongly")
+	}
+	if _, err := os.Stat(legacyPath); err != nil {
+		t.Fatalf("legacy file migrated by a read: %v", err)
+	}
+
+	cache.migrateLegacyEntries()
+	cache.ClearMemoryCache()
+	if cache.Get("avatar") == nil {
+		t.Fatal("migrated entry not readable")
+	}
+	if _, err := os.Stat(legacyPath); !os.IsNotExist(err) {
+		t.Fatal("legacy file left in place")
+	}
+	if _, err := os.Stat(cache.metaPath("avatar")); err != nil {
+		t.Fatalf("migrated entry has no metadata: %v", err)
+	}
+}
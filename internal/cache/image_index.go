@@ -0,0 +1,225 @@
+package cache
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Disk image eviction.
+const (
+	imageEvictionBatch  = 64 // Entries removed per pass; the index is unlocked between passes
+	imageEvictionTarget = 90 // Percent of MaxCacheSizeBytes eviction stops at, so it does not run on every write
+)
+
+// diskEntry is the index record of a cached image.
+type diskEntry struct {
+	size       int64 // Bytes of the data and metadata files
+	lastAccess time.Time
+}
+
+// imageIndex tracks the cached images on disk and their total size.
+// Access times are persisted as the data files' modification times, so the index is rebuilt from the
+// directory on startup and needs no file of its own that a crash could leave inconsistent.
+// Guarded by ImageCache.indexMutex.
+type imageIndex struct {
+	entries map[string]*diskEntry
+	total   int64
+	touched map[string]bool // Accessed since their file times were last updated
+	loaded  bool            // The directory was indexed; before that, entries only holds new writes
+}
+
+// indexKey returns the index key of an image ID: the path the ID maps to, relative to the cache directory.
+func indexKey(imageID string) string {
+	return filepath.ToSlash(filepath.Clean(imageID))
+}
+
+// loadIndex rebuilds the index from the cache directory. Leftovers of interrupted writes and
+// evictions (temporary files, metadata without data) are removed. Entries written or read while
+// the directory was walked are kept, so the cache can be used during startup.
+func (cache *ImageCache) loadIndex() {
+	entries := make(map[string]*diskEntry)
+	var orphans []string
+
+	_ = filepath.WalkDir(cache.cacheDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		relative, err := filepath.Rel(cache.cacheDir, path)
+		if err != nil {
+			return nil
+		}
+		relative = filepath.ToSlash(relative)
+
+		switch {
+		case strings.HasSuffix(relative, ".tmp"):
+			orphans = append(orphans, path)
+		case strings.HasSuffix(relative, imageDataExtension):
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			entry := entryFor(entries, strings.TrimSuffix(relative, imageDataExtension))
+			entry.size += info.Size()
+			entry.lastAccess = info.ModTime()
+		case strings.HasSuffix(relative, imageMetaExtension):
+			if info, err := d.Info(); err == nil {
+				entryFor(entries, strings.TrimSuffix(relative, imageMetaExtension)).size += info.Size()
+			}
+		}
+		return nil
+	})
+
+	// Metadata whose data is gone: the eviction removing the entry was interrupted
+	for key, entry := range entries {
+		if entry.lastAccess.IsZero() {
+			orphans = append(orphans, cache.metaPath(key))
+			delete(entries, key)
+		}
+	}
+	for _, path := range orphans {
+		_ = os.Remove(path)
+	}
+
+	cache.indexMutex.Lock()
+	defer cache.indexMutex.Unlock()
+
+	// Writes and reads made meanwhile are newer than what the walk found
+	for key, entry := range cache.index.entries {
+		entries[key] = entry
+	}
+	now := time.Now()
+	for key := range cache.index.touched {
+		if entry := entries[key]; entry != nil {
+			entry.lastAccess = now
+		}
+	}
+
+	var total int64
+	for _, entry := range entries {
+		total += entry.size
+	}
+	cache.index.entries = entries
+	cache.index.total = total
+	cache.index.loaded = true
+}
+
+// entryFor returns the entry of a key, adding it if needed.
+func entryFor(entries map[string]*diskEntry, key string) *diskEntry {
+	entry := entries[key]
+	if entry == nil {
+		entry = &diskEntry{}
+		entries[key] = entry
+	}
+	return entry
+}
+
+// recordWrite adds or replaces an entry after its files were written.
+func (cache *ImageCache) recordWrite(imageID string, size int64) {
+	key := indexKey(imageID)
+
+	cache.indexMutex.Lock()
+	defer cache.indexMutex.Unlock()
+
+	entry := entryFor(cache.index.entries, key)
+	cache.index.total += size - entry.size
+	entry.size = size
+	entry.lastAccess = time.Now()
+	delete(cache.index.touched, key) // The write set the file time
+}
+
+// recordAccess marks an entry as just used. Its file time is updated on the next flush.
+func (cache *ImageCache) recordAccess(imageID string) {
+	key := indexKey(imageID)
+
+	cache.indexMutex.Lock()
+	defer cache.indexMutex.Unlock()
+
+	if entry := cache.index.entries[key]; entry != nil {
+		entry.lastAccess = time.Now()
+		cache.index.touched[key] = true
+	} else if !cache.index.loaded {
+		cache.index.touched[key] = true // Applied once the entry is indexed
+	}
+}
+
+// persistAccessTimes writes the access times of recently used entries to their data files.
+func (cache *ImageCache) persistAccessTimes() {
+	cache.indexMutex.Lock()
+	touched := make(map[string]time.Time, len(cache.index.touched))
+	for key := range cache.index.touched {
+		if entry := cache.index.entries[key]; entry != nil {
+			touched[key] = entry.lastAccess
+		}
+	}
+	cache.index.touched = make(map[string]bool)
+	cache.indexMutex.Unlock()
+
+	for key, lastAccess := range touched {
+		_ = os.Chtimes(cache.dataPath(key), lastAccess, lastAccess)
+	}
+}
+
+// evictOverBudget removes the least recently used entries until the cache is under budget,
+// a batch at a time so reads and writes are not held up meanwhile.
+// Only called from the goroutine that writes entries, so an entry is never written while evicted.
+func (cache *ImageCache) evictOverBudget() {
+	cache.mutex.RLock()
+	limit := cache.MaxCacheSizeBytes
+	cache.mutex.RUnlock()
+	target := limit / 100 * imageEvictionTarget
+
+	cache.indexMutex.Lock()
+	if cache.index.total <= limit {
+		cache.indexMutex.Unlock()
+		return
+	}
+	started := time.Now()
+	keys := make([]string, 0, len(cache.index.entries))
+	for key := range cache.index.entries {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		return cache.index.entries[a].lastAccess.Compare(cache.index.entries[b].lastAccess)
+	})
+	cache.indexMutex.Unlock()
+
+	for batch := range slices.Chunk(keys, imageEvictionBatch) {
+		if !cache.evictBatch(batch, target, started) {
+			return
+		}
+	}
+}
+
+// evictBatch removes the entries of a batch that were not used since eviction started, stopping once
+// the cache is at target. Returns false if it reached the target.
+func (cache *ImageCache) evictBatch(keys []string, target int64, started time.Time) bool {
+	var victims []string
+
+	cache.indexMutex.Lock()
+	for _, key := range keys {
+		if cache.index.total <= target {
+			break
+		}
+		entry := cache.index.entries[key]
+		if entry == nil || entry.lastAccess.After(started) {
+			continue
+		}
+		cache.index.total -= entry.size
+		delete(cache.index.entries, key)
+		delete(cache.index.touched, key)
+		victims = append(victims, key)
+	}
+	done := cache.index.total <= target
+	cache.indexMutex.Unlock()
+
+	// Data first: metadata left by an interrupted eviction is removed by the next loadIndex
+	for _, key := range victims {
+		_ = os.Remove(cache.dataPath(key))
+		_ = os.Remove(cache.metaPath(key))
+	}
+	return !done
+}
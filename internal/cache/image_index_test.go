@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"bytes"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// cacheImages stores n PNG images named img-0 to img-(n-1) and writes them to disk in order.
+// Returns the average size of an entry.
+func cacheImages(t *testing.T, cache *ImageCache, n int) int64 {
+	t.Helper()
+
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, testImage()); err != nil {
+		t.Fatal(err)
+	}
+	for i := range n {
+		imageID := "img-" + string(rune('0'+i))
+		if _, err := cache.Set(imageID, "", "image/png", encoded.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		cache.FlushToDisk()
+	}
+	return cache.GetCacheSize() / int64(n)
+}
+
+func TestEvictsLeastRecentlyUsedImages(t *testing.T) {
+	cache := newImageCache(t.TempDir())
+	entrySize := cacheImages(t, cache, 5)
+
+	// img-0 is used again, so img-1 and img-2 are the least recently used
+	cache.ClearMemoryCache()
+	cache.Get("img-0")
+
+	// Eviction goes below the budget, to imageEvictionTarget percent: room for 3.6 entries
+	cache.SetMaxCacheSize(entrySize * 4)
+	cache.evictOverBudget()
+
+	for imageID, kept := range map[string]bool{"img-0": true, "img-1": false, "img-2": false, "img-3": true, "img-4": true} {
+		_, err := os.Stat(cache.dataPath(imageID))
+		if exists := err == nil; exists != kept {
+			t.Errorf("%s on disk = %v, want %v", imageID, exists, kept)
+		}
+	}
+	if got, limit := cache.GetCacheSize(), entrySize*4/100*imageEvictionTarget; got > limit {
+		t.Fatalf("GetCacheSize() = %d, want at most %d", got, limit)
+	}
+}
+
+func TestLoadIndexCleansInterruptedWrites(t *testing.T) {
+	dir := t.TempDir()
+	cache := newImageCache(dir)
+	cacheImages(t, cache, 2)
+	written := cache.GetCacheSize()
+
+	// A write and an eviction interrupted by a crash
+	leftovers := []string{
+		filepath.Join(dir, "img-2.img.123.tmp"),
+		cache.metaPath("img-3"),
+	}
+	for _, path := range leftovers {
+		if err := os.WriteFile(path, []byte("partial"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reloaded := newImageCache(dir)
+	reloaded.loadIndex()
+
+	if got, want := reloaded.GetCacheSize(), written; got != want {
+		t.Fatalf("GetCacheSize() = %d, want %d", got, want)
+	}
+	for _, path := range leftovers {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s left in place", filepath.Base(path))
+		}
+	}
+}
+
+func TestPurgeEmptiesIndex(t *testing.T) {
+	cache := newImageCache(t.TempDir())
+	cacheImages(t, cache, 2)
+
+	cache.PurgeCache()
+
+	if got := cache.GetCacheSize(); got != 0 {
+		t.Fatalf("GetCacheSize() = %d after purge, want 0", got)
+	}
+	cache.indexMutex.Lock()
+	defer cache.indexMutex.Unlock()
+	if len(cache.index.entries) != 0 {
+		t.Fatalf("index has %d entries after purge, want 0", len(cache.index.entries))
+	}
+}
+
+func TestCacheIsUsableWhileIndexing(t *testing.T) {
+	dir := t.TempDir()
+	entrySize := cacheImages(t, newImageCache(dir), 3)
+
+	// Before the directory is indexed, images are read from their files and their use is remembered
+	reloaded := newImageCache(dir)
+	if reloaded.Get("img-0") == nil {
+		t.Fatal("Get() = nil before the index was loaded")
+	}
+	reloaded.loadIndex()
+
+	// img-0 was used last, so eviction keeps it
+	reloaded.SetMaxCacheSize(entrySize * 2)
+	reloaded.evictOverBudget()
+	if _, err := os.Stat(reloaded.dataPath("img-0")); err != nil {
+		t.Fatalf("img-0 evicted although used while indexing: %v", err)
+	}
+}
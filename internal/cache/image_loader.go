@@ -0,0 +1,356 @@
+package cache
+
+import (
+	"container/heap"
+	"context"
+	"image"
+	"sync"
+
+	"fyne.io/fyne/v2"
+)
+
+// imageDownloadWorkers is how many images are read from disk or downloaded at once.
+const imageDownloadWorkers = 6
+
+// LoadPriority orders queued image loads; lower values start first.
+type LoadPriority int
+
+const (
+	PriorityVisible   LoadPriority = iota // On screen
+	PriorityOffscreen                     // Rendered, but scrolled out of view
+	PriorityPrefetch                      // Not rendered yet
+)
+
+// ImageScope groups the image loads of a view, such as the messages of a channel.
+// Loads belong to owners (message IDs) whose priority the view updates as it scrolls;
+// cancelling the scope drops its queued loads and aborts downloads nothing else waits for.
+// A nil scope is valid: its loads are unscoped.
+type ImageScope struct {
+	cache     *ImageCache
+	cancelled bool            // Guarded by cache.loadMutex
+	visible   map[string]bool // Owner keys on screen. Guarded by cache.loadMutex
+}
+
+// ImageOwner is what an image is loaded for: a key within a scope. The zero value is unscoped:
+// its loads are never cancelled and always treated as visible.
+type ImageOwner struct {
+	scope *ImageScope
+	key   string
+}
+
+// imageWaiter is one caller waiting for an image.
+type imageWaiter struct {
+	owner    ImageOwner
+	priority LoadPriority // Requested priority; an owner on screen raises it to PriorityVisible
+	circular bool
+	onLoaded func(image.Image) // Nil for prefetches
+}
+
+// imageRequest loads one image for every caller waiting for it.
+type imageRequest struct {
+	key      string // imageID, or the URL of images without one
+	imageID  string
+	url      string
+	waiters  []*imageWaiter
+	priority LoadPriority
+	order    uint64             // Queue order within a priority
+	index    int                // Position in the queue; -1 once a worker took it
+	cancel   context.CancelFunc // Aborts the download once started
+}
+
+// imageQueue is a heap of requests not yet started, by priority, then oldest first.
+type imageQueue []*imageRequest
+
+func (q imageQueue) Len() int { return len(q) }
+
+func (q imageQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority < q[j].priority
+	}
+	return q[i].order < q[j].order
+}
+
+func (q imageQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *imageQueue) Push(x any) {
+	request := x.(*imageRequest)
+	request.index = len(*q)
+	*q = append(*q, request)
+}
+
+func (q *imageQueue) Pop() any {
+	old := *q
+	request := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	request.index = -1
+	return request
+}
+
+// imageLoader coalesces image loads by image and runs them on a fixed pool of workers.
+// Guarded by ImageCache.loadMutex.
+type imageLoader struct {
+	inflight map[string]*imageRequest // Request key → queued or running request
+	queue    imageQueue
+	wake     *sync.Cond // Signalled when a request is queued
+	order    uint64
+	started  bool // Workers are running
+}
+
+// NewScope creates an image scope, for the loads of a view that is about to be shown.
+func (cache *ImageCache) NewScope() *ImageScope {
+	return &ImageScope{cache: cache, visible: make(map[string]bool)}
+}
+
+// Owner returns the owner of loads made for key, such as a message ID.
+func (scope *ImageScope) Owner(key string) ImageOwner {
+	return ImageOwner{scope: scope, key: key}
+}
+
+// SetVisible sets the owners that are on screen. Their queued loads start before the scope's others.
+func (scope *ImageScope) SetVisible(keys []string) {
+	if scope == nil {
+		return
+	}
+
+	cache := scope.cache
+	cache.loadMutex.Lock()
+	defer cache.loadMutex.Unlock()
+
+	scope.visible = make(map[string]bool, len(keys))
+	for _, key := range keys {
+		scope.visible[key] = true
+	}
+
+	for _, request := range cache.loader.queue {
+		request.priority = request.currentPriority()
+	}
+	heap.Init(&cache.loader.queue)
+}
+
+// Cancel drops the scope's loads: queued ones are removed and downloads nothing else waits for are aborted.
+// Images that arrive later are not delivered to the scope.
+func (scope *ImageScope) Cancel() {
+	if scope == nil {
+		return
+	}
+
+	cache := scope.cache
+	cache.loadMutex.Lock()
+	defer cache.loadMutex.Unlock()
+
+	scope.cancelled = true
+	for key, request := range cache.loader.inflight {
+		live := request.waiters[:0]
+		for _, waiter := range request.waiters {
+			if waiter.owner.scope != scope {
+				live = append(live, waiter)
+			}
+		}
+		clear(request.waiters[len(live):])
+		request.waiters = live
+
+		switch {
+		case len(live) > 0 && request.index >= 0:
+			cache.reprioritize(request)
+		case len(live) > 0:
+			// Running for other callers
+		case request.index >= 0:
+			heap.Remove(&cache.loader.queue, request.index)
+			delete(cache.loader.inflight, key)
+		default:
+			request.cancel()
+			delete(cache.loader.inflight, key)
+		}
+	}
+}
+
+// LoadImageToContainer is ImageCache.LoadImageToContainer for this owner.
+func (owner ImageOwner) LoadImageToContainer(imageID, url string, size fyne.Size, target *fyne.Container, circular bool, background fyne.CanvasObject) {
+	owner.cache().loadImageToContainer(owner, imageID, url, size, target, circular, background)
+}
+
+// LoadFromURLAsync is ImageCache.LoadFromURLAsync for this owner.
+func (owner ImageOwner) LoadFromURLAsync(imageID, url string, circular bool, onLoaded func(image.Image)) {
+	owner.cache().load(owner, imageID, url, PriorityVisible, circular, onLoaded)
+}
+
+// Prefetch loads an image into the cache ahead of the widgets that will show it.
+func (owner ImageOwner) Prefetch(imageID, url string) {
+	owner.cache().load(owner, imageID, url, PriorityPrefetch, false, nil)
+}
+
+// cache returns the image cache loads of the owner go through.
+func (owner ImageOwner) cache() *ImageCache {
+	if owner.scope == nil {
+		return GetImageCache()
+	}
+	return owner.scope.cache
+}
+
+// live reports whether the owner still wants its images. Call with loadMutex held.
+func (owner ImageOwner) live() bool {
+	return owner.scope == nil || !owner.scope.cancelled
+}
+
+// currentPriority returns the waiter's priority, given whether its owner is on screen.
+// Call with loadMutex held.
+func (waiter *imageWaiter) currentPriority() LoadPriority {
+	scope := waiter.owner.scope
+	switch {
+	case scope == nil:
+		return waiter.priority
+	case scope.visible[waiter.owner.key]:
+		return PriorityVisible
+	default:
+		return max(waiter.priority, PriorityOffscreen)
+	}
+}
+
+// currentPriority returns the highest priority of the request's waiters. Call with loadMutex held.
+func (request *imageRequest) currentPriority() LoadPriority {
+	priority := PriorityPrefetch
+	for _, waiter := range request.waiters {
+		priority = min(priority, waiter.currentPriority())
+	}
+	return priority
+}
+
+// load queues an image for a caller, joining the request already made for it if any.
+// Images in memory are passed to onLoaded at once.
+func (cache *ImageCache) load(owner ImageOwner, imageID, url string, priority LoadPriority, circular bool, onLoaded func(image.Image)) {
+	if url == "" {
+		return
+	}
+
+	// Fast path: memory only, so the UI thread never waits for the disk
+	if img := cache.getMemory(imageID); img != nil {
+		if onLoaded != nil {
+			if circular {
+				img = circleClip(img)
+			}
+			onLoaded(img)
+		}
+		return
+	}
+
+	key := imageID
+	if key == "" {
+		key = url
+	}
+
+	cache.loadMutex.Lock()
+	defer cache.loadMutex.Unlock()
+
+	if !owner.live() {
+		return
+	}
+
+	waiter := &imageWaiter{owner: owner, priority: priority, circular: circular, onLoaded: onLoaded}
+	if request := cache.loader.inflight[key]; request != nil {
+		request.waiters = append(request.waiters, waiter)
+		if request.index >= 0 {
+			cache.reprioritize(request)
+		}
+		return
+	}
+
+	cache.loader.order++
+	request := &imageRequest{
+		key:     key,
+		imageID: imageID,
+		url:     url,
+		waiters: []*imageWaiter{waiter},
+		order:   cache.loader.order,
+	}
+	request.priority = request.currentPriority()
+	cache.loader.inflight[key] = request
+	heap.Push(&cache.loader.queue, request)
+
+	if !cache.loader.started {
+		cache.loader.started = true
+		for range imageDownloadWorkers {
+			go cache.loadWorker()
+		}
+	}
+	cache.loader.wake.Signal()
+}
+
+// reprioritize moves a queued request after its waiters changed. Call with loadMutex held.
+func (cache *ImageCache) reprioritize(request *imageRequest) {
+	if priority := request.currentPriority(); priority != request.priority {
+		request.priority = priority
+		heap.Fix(&cache.loader.queue, request.index)
+	}
+}
+
+// loadWorker runs queued requests, highest priority first, for the lifetime of the cache.
+func (cache *ImageCache) loadWorker() {
+	for {
+		cache.loadMutex.Lock()
+		for len(cache.loader.queue) == 0 {
+			cache.loader.wake.Wait()
+		}
+		request := heap.Pop(&cache.loader.queue).(*imageRequest)
+		ctx, cancel := context.WithCancel(context.Background())
+		request.cancel = cancel
+		cache.loadMutex.Unlock()
+
+		img := cache.readDisk(request.imageID)
+		if img == nil {
+			img = cache.download(ctx, request.imageID, request.url)
+		}
+		cancel()
+
+		cache.loadMutex.Lock()
+		if cache.loader.inflight[request.key] == request {
+			delete(cache.loader.inflight, request.key)
+		}
+		waiters := request.waiters
+		request.waiters = nil
+		cache.loadMutex.Unlock()
+
+		if img != nil {
+			cache.deliver(img, waiters)
+		}
+	}
+}
+
+// deliver passes a loaded image to its waiters on the UI thread, skipping those whose scope was cancelled.
+func (cache *ImageCache) deliver(img image.Image, waiters []*imageWaiter) {
+	var clipped image.Image // Shared by the waiters wanting a circle
+	results := make([]image.Image, len(waiters))
+	wanted := false
+	for i, waiter := range waiters {
+		if waiter.onLoaded == nil {
+			continue // A prefetch
+		}
+		results[i] = img
+		if waiter.circular {
+			if clipped == nil {
+				clipped = circleClip(img)
+			}
+			results[i] = clipped
+		}
+		wanted = true
+	}
+	if !wanted {
+		return
+	}
+
+	fyne.CurrentApp().Driver().DoFromGoroutine(func() {
+		for i, waiter := range waiters {
+			cache.loadMutex.Lock()
+			live := waiter.owner.live()
+			cache.loadMutex.Unlock()
+
+			if live && results[i] != nil {
+				waiter.onLoaded(results[i])
+			}
+		}
+	}, false)
+}
@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"container/heap"
+	"image"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+)
+
+// pausedLoader returns a cache whose loads are queued but never started, so the queue can be inspected.
+func pausedLoader(t *testing.T) *ImageCache {
+	cache := newImageCache(t.TempDir())
+	cache.loader.started = true
+	return cache
+}
+
+// queuedKeys pops every queued request, in the order workers would start them.
+func queuedKeys(cache *ImageCache) []string {
+	cache.loadMutex.Lock()
+	defer cache.loadMutex.Unlock()
+
+	var keys []string
+	for cache.loader.queue.Len() > 0 {
+		keys = append(keys, heap.Pop(&cache.loader.queue).(*imageRequest).key)
+	}
+	return keys
+}
+
+func TestCoalescesLoads(t *testing.T) {
+	test.NewTempApp(t)
+
+	var requests atomic.Int32
+	photo := encodedImage(t, 8, 8)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(20 * time.Millisecond) // Let every caller join before the image lands
+		_, _ = w.Write(photo)
+	}))
+	defer srv.Close()
+
+	cache := newImageCache(t.TempDir())
+	cache.client = srv.Client()
+
+	const callers = 10
+	loaded := make(chan image.Image, callers)
+	for range callers {
+		cache.LoadFromURLAsync("avatar", srv.URL, true, func(img image.Image) { loaded <- img })
+	}
+
+	for range callers {
+		select {
+		case <-loaded:
+		case <-time.After(5 * time.Second):
+			t.Fatal("image not delivered to every caller")
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("requests = %d, want 1", got)
+	}
+}
+
+func TestVisibleLoadsStartFirst(t *testing.T) {
+	cache := pausedLoader(t)
+	scope := cache.NewScope()
+
+	scope.Owner("old").Prefetch("prefetched", "https://example.com/p")
+	scope.Owner("old").LoadFromURLAsync("offscreen", "https://example.com/o", false, func(image.Image) {})
+	scope.Owner("new").LoadFromURLAsync("onscreen", "https://example.com/v", false, func(image.Image) {})
+	cache.LoadFromURLAsync("icon", "https://example.com/i", true, func(image.Image) {})
+	scope.SetVisible([]string{"new"})
+
+	// A rendered widget joining the prefetch raises it to offscreen, where it keeps its place in line
+	scope.Owner("old").LoadFromURLAsync("prefetched", "https://example.com/p", false, func(image.Image) {})
+
+	want := []string{"onscreen", "icon", "prefetched", "offscreen"}
+	got := queuedKeys(cache)
+	if len(got) != len(want) {
+		t.Fatalf("queue = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("queue = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestCancelledScopeDropsLoads(t *testing.T) {
+	cache := pausedLoader(t)
+	scope := cache.NewScope()
+
+	scope.Owner("m1").LoadFromURLAsync("attachment", "https://example.com/a", false, func(image.Image) {})
+	scope.Owner("m1").LoadFromURLAsync("avatar", "https://example.com/b", true, func(image.Image) {})
+	cache.LoadFromURLAsync("avatar", "https://example.com/b", true, func(image.Image) {}) // Also in the member list
+	scope.Cancel()
+
+	// Loads made after switching away are ignored
+	scope.Owner("m2").LoadFromURLAsync("late", "https://example.com/c", false, func(image.Image) {})
+
+	got := queuedKeys(cache)
+	if len(got) != 1 || got[0] != "avatar" {
+		t.Fatalf("queue = %v, want only the avatar still shown elsewhere", got)
+	}
+	if waiters := cache.loader.inflight["avatar"].waiters; len(waiters) != 1 || waiters[0].owner.scope != nil {
+		t.Fatalf("avatar waiters = %d, want the unscoped one", len(waiters))
+	}
+}
@@ -0,0 +1,205 @@
+package cache
+
+import (
+	"container/list"
+	"image"
+)
+
+// Decoded image budgets. Small images are avatars, server icons and emojis; they are requested at
+// 256 pixels or less, so anything with more pixels than that is treated as an attachment or banner.
+const (
+	DefaultSmallImageBudget int64 = 64 * 1024 * 1024  // Decoded pixel bytes of small images (64 MB)
+	DefaultLargeImageBudget int64 = 256 * 1024 * 1024 // Decoded pixel bytes of large images (256 MB)
+
+	smallImageMaxPixels = 256 * 256
+)
+
+// ImageMemoryUsage describes one class of decoded images held in memory.
+type ImageMemoryUsage struct {
+	Entries   int
+	Bytes     int64 // Decoded pixel bytes
+	Budget    int64
+	Evictions uint64
+}
+
+// ImageMemoryStats describes the decoded images held in memory.
+type ImageMemoryStats struct {
+	Small  ImageMemoryUsage // Avatars, icons and emojis
+	Large  ImageMemoryUsage // Attachments and banners
+	Hits   uint64           // Lookups answered from memory
+	Misses uint64           // Lookups that had to go to disk or the network
+}
+
+// memoryEntry is a decoded image in an imageLRU.
+type memoryEntry struct {
+	imageID string
+	image   image.Image
+	bytes   int64
+}
+
+// imageLRU holds decoded images up to a budget of pixel bytes, evicting the least recently used first.
+type imageLRU struct {
+	order     *list.List // *memoryEntry, most recently used first
+	entries   map[string]*list.Element
+	bytes     int64
+	budget    int64
+	evictions uint64
+}
+
+// newImageLRU creates an empty LRU with a budget in bytes.
+func newImageLRU(budget int64) *imageLRU {
+	return &imageLRU{
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+		budget:  budget,
+	}
+}
+
+// get returns an image and marks it as just used, or nil.
+func (lru *imageLRU) get(imageID string) image.Image {
+	element := lru.entries[imageID]
+	if element == nil {
+		return nil
+	}
+	lru.order.MoveToFront(element)
+	return element.Value.(*memoryEntry).image
+}
+
+// add stores an image as the most recently used and evicts others beyond the budget.
+// An image larger than the whole budget is not kept.
+func (lru *imageLRU) add(imageID string, img image.Image, bytes int64) {
+	lru.remove(imageID)
+	if bytes > lru.budget {
+		return
+	}
+
+	lru.entries[imageID] = lru.order.PushFront(&memoryEntry{imageID: imageID, image: img, bytes: bytes})
+	lru.bytes += bytes
+	lru.evictOverBudget()
+}
+
+// remove drops an image, if present.
+func (lru *imageLRU) remove(imageID string) {
+	if element := lru.entries[imageID]; element != nil {
+		lru.bytes -= element.Value.(*memoryEntry).bytes
+		lru.order.Remove(element)
+		delete(lru.entries, imageID)
+	}
+}
+
+// setBudget changes the budget, evicting images beyond it.
+func (lru *imageLRU) setBudget(budget int64) {
+	lru.budget = budget
+	lru.evictOverBudget()
+}
+
+// evictOverBudget removes the least recently used images until the LRU is within budget.
+func (lru *imageLRU) evictOverBudget() {
+	for lru.bytes > lru.budget {
+		oldest := lru.order.Back()
+		if oldest == nil {
+			return
+		}
+		lru.remove(oldest.Value.(*memoryEntry).imageID)
+		lru.evictions++
+	}
+}
+
+// clear removes every image.
+func (lru *imageLRU) clear() {
+	lru.order.Init()
+	lru.entries = make(map[string]*list.Element)
+	lru.bytes = 0
+}
+
+// usage returns the LRU's statistics.
+func (lru *imageLRU) usage() ImageMemoryUsage {
+	return ImageMemoryUsage{
+		Entries:   len(lru.entries),
+		Bytes:     lru.bytes,
+		Budget:    lru.budget,
+		Evictions: lru.evictions,
+	}
+}
+
+// imageMemory holds decoded images in two LRUs, so a few large attachments cannot push out
+// the avatars and icons shown everywhere. Guarded by ImageCache.mutex.
+type imageMemory struct {
+	small  *imageLRU
+	large  *imageLRU
+	hits   uint64
+	misses uint64
+}
+
+// newImageMemory creates an empty in-memory cache with the default budgets.
+func newImageMemory() imageMemory {
+	return imageMemory{
+		small: newImageLRU(DefaultSmallImageBudget),
+		large: newImageLRU(DefaultLargeImageBudget),
+	}
+}
+
+// get returns a decoded image, or nil.
+func (memory *imageMemory) get(imageID string) image.Image {
+	img := memory.small.get(imageID)
+	if img == nil {
+		img = memory.large.get(imageID)
+	}
+
+	if img == nil {
+		memory.misses++
+	} else {
+		memory.hits++
+	}
+	return img
+}
+
+// add stores a decoded image in the LRU of its size class.
+func (memory *imageMemory) add(imageID string, img image.Image) {
+	bounds := img.Bounds()
+	if bounds.Dx()*bounds.Dy() <= smallImageMaxPixels {
+		memory.large.remove(imageID)
+		memory.small.add(imageID, img, pixelBytes(img))
+	} else {
+		memory.small.remove(imageID)
+		memory.large.add(imageID, img, pixelBytes(img))
+	}
+}
+
+// clear removes every image. Statistics are kept.
+func (memory *imageMemory) clear() {
+	memory.small.clear()
+	memory.large.clear()
+}
+
+// stats returns the statistics of both LRUs.
+func (memory *imageMemory) stats() ImageMemoryStats {
+	return ImageMemoryStats{
+		Small:  memory.small.usage(),
+		Large:  memory.large.usage(),
+		Hits:   memory.hits,
+		Misses: memory.misses,
+	}
+}
+
+// pixelBytes returns the memory held by a decoded image's pixels.
+func pixelBytes(img image.Image) int64 {
+	switch img := img.(type) {
+	case *image.RGBA:
+		return int64(len(img.Pix))
+	case *image.NRGBA:
+		return int64(len(img.Pix))
+	case *image.Gray:
+		return int64(len(img.Pix))
+	case *image.Paletted:
+		return int64(len(img.Pix))
+	case *image.YCbCr:
+		return int64(len(img.Y) + len(img.Cb) + len(img.Cr))
+	case *image.NYCbCrA:
+		return int64(len(img.Y) + len(img.Cb) + len(img.Cr) + len(img.A))
+	}
+
+	// Other formats are assumed to take four bytes per pixel
+	bounds := img.Bounds()
+	return int64(bounds.Dx()) * int64(bounds.Dy()) * 4
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+)
+
+// encodedImage returns a PNG of the given size.
+func encodedImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, image.NewNRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return encoded.Bytes()
+}
+
+func TestMemoryStaysWithinBudgets(t *testing.T) {
+	cache := newImageCache(t.TempDir())
+
+	avatar := encodedImage(t, 64, 64)  // 16 KB decoded
+	photo := encodedImage(t, 512, 512) // 1 MB decoded
+	cache.SetMemoryBudgets(3*64*64*4, 2*512*512*4)
+
+	for _, imageID := range []string{"avatar-0", "avatar-1", "avatar-2", "avatar-3"} {
+		if _, err := cache.Set(imageID, "", "image/png", avatar); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// avatar-1 is used again, so avatar-2 is the next to go
+	cache.Get("avatar-1")
+	for _, imageID := range []string{"photo-0", "photo-1", "photo-2"} {
+		if _, err := cache.Set(imageID, "", "image/png", photo); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := cache.Set("avatar-4", "", "image/png", avatar); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := cache.MemoryStats()
+	for name, usage := range map[string]ImageMemoryUsage{"small": stats.Small, "large": stats.Large} {
+		if usage.Bytes > usage.Budget {
+			t.Errorf("%s images use %d bytes, over the budget of %d", name, usage.Bytes, usage.Budget)
+		}
+	}
+	if stats.Small.Entries != 3 || stats.Large.Entries != 2 {
+		t.Errorf("entries = %d small, %d large; want 3 and 2", stats.Small.Entries, stats.Large.Entries)
+	}
+	if stats.Small.Evictions != 2 || stats.Large.Evictions != 1 {
+		t.Errorf("evictions = %d small, %d large; want 2 and 1", stats.Small.Evictions, stats.Large.Evictions)
+	}
+
+	// Photos never push out avatars: only the least recently used avatars were evicted
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	for imageID, kept := range map[string]bool{
+		"avatar-0": false, "avatar-1": true, "avatar-2": false, "avatar-3": true, "avatar-4": true,
+		"photo-0": false, "photo-1": true, "photo-2": true,
+	} {
+		if inMemory := cache.memory.get(imageID) != nil; inMemory != kept {
+			t.Errorf("%s in memory = %v, want %v", imageID, inMemory, kept)
+		}
+	}
+}
+
+func TestMemorySkipsImagesOverBudget(t *testing.T) {
+	cache := newImageCache(t.TempDir())
+	cache.SetMemoryBudgets(DefaultSmallImageBudget, 512*512*4-1)
+
+	img, err := cache.Set("photo", "", "image/png", encodedImage(t, 512, 512))
+	if err != nil || img == nil {
+		t.Fatalf("Set() = %v, %v; want the decoded image", img, err)
+	}
+	if stats := cache.MemoryStats(); stats.Large.Entries != 0 || stats.Large.Bytes != 0 {
+		t.Fatalf("large usage = %+v, want nothing kept", stats.Large)
+	}
+}
@@ -1,8 +1,10 @@
 package cache
 
 import (
+	"bytes"
+	"context"
 	"image"
-	"image/png"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -21,15 +23,24 @@ import (
 )
 
 // ImageCache manages image caching with in-memory storage and periodic disk persistence.
+// Images are kept on disk as downloaded (see image_disk.go) and decoded when read; decoded images
+// stay in memory within a budget (see image_memory.go).
 type ImageCache struct {
 	mutex             sync.RWMutex
-	memory            map[string]image.Image
-	pending           map[string]image.Image // Images waiting to be saved to disk
+	memory            imageMemory
+	pending           map[string]imageEntry // Downloads waiting to be saved to disk
 	cacheDir          string
 	client            *http.Client
 	saveTimer         *time.Ticker
 	stopChan          chan struct{}
-	MaxCacheSizeBytes int64 // Maximum cache size in bytes (default 5GB)
+	doneChan          chan struct{} // Closed once the periodic save goroutine has stopped
+	MaxCacheSizeBytes int64         // Maximum cache size in bytes (default 5GB)
+
+	indexMutex sync.Mutex
+	index      imageIndex // Entries on disk; see image_index.go
+
+	loadMutex sync.Mutex
+	loader    imageLoader // Queued and running loads; see image_loader.go
 }
 
 // DefaultMaxCacheSizeBytes is the default maximum cache size (5 GB).
@@ -43,51 +54,74 @@ var (
 // GetImageCache returns the global image cache instance.
 func GetImageCache() *ImageCache {
 	imageCacheOnce.Do(func() {
-		cacheDirectory := getAppCacheDir()
-		globalImageCache = &ImageCache{
-			memory:            make(map[string]image.Image),
-			pending:           make(map[string]image.Image),
-			cacheDir:          cacheDirectory,
-			client:            &http.Client{Timeout: 15 * time.Second},
-			stopChan:          make(chan struct{}),
-			MaxCacheSizeBytes: DefaultMaxCacheSizeBytes,
-		}
+		cacheDirectory := getAppCacheDir("assets", "images")
+		globalImageCache = newImageCache(cacheDirectory)
 
 		// Create cache directory
 		if err := os.MkdirAll(cacheDirectory, 0755); err != nil {
 			println("Warning: Failed to create image cache directory:", err.Error())
 		}
 
-		// Check and purge cache if it exceeds the size limit
-		globalImageCache.CheckAndPurgeCache()
-
-		// Start periodic save goroutine (every 2 minutes)
+		// Start periodic save goroutine (every 2 minutes); it first indexes the disk cache, then evicts entries over budget.
+		// Until the index is built, reads go to the files directly.
 		globalImageCache.startPeriodicSave(2 * time.Minute)
 	})
 	return globalImageCache
 }
 
-// getAppCacheDir returns the application cache directory path.
-func getAppCacheDir() string {
-	if cacheDirectory, err := os.UserCacheDir(); err == nil {
-		return filepath.Join(cacheDirectory, "RGOClient", "assets", "images")
+// newImageCache creates an image cache kept in a directory.
+func newImageCache(cacheDirectory string) *ImageCache {
+	cache := &ImageCache{
+		memory:            newImageMemory(),
+		pending:           make(map[string]imageEntry),
+		cacheDir:          cacheDirectory,
+		client:            &http.Client{Timeout: 15 * time.Second},
+		stopChan:          make(chan struct{}),
+		doneChan:          make(chan struct{}),
+		MaxCacheSizeBytes: DefaultMaxCacheSizeBytes,
+		index: imageIndex{
+			entries: make(map[string]*diskEntry),
+			touched: make(map[string]bool),
+		},
+		loader: imageLoader{inflight: make(map[string]*imageRequest)},
 	}
-	if homeDirectory, err := os.UserHomeDir(); err == nil {
-		return filepath.Join(homeDirectory, ".cache", "RGOClient", "assets", "images")
+	cache.loader.wake = sync.NewCond(&cache.loadMutex)
+	return cache
+}
+
+// getAppCacheDir returns a directory under the application cache directory.
+func getAppCacheDir(elem ...string) string {
+	root := filepath.Join(".", "cache")
+	if cacheDirectory, err := os.UserCacheDir(); err == nil {
+		root = filepath.Join(cacheDirectory, "RGOClient")
+	} else if homeDirectory, err := os.UserHomeDir(); err == nil {
+		root = filepath.Join(homeDirectory, ".cache", "RGOClient")
 	}
-	return filepath.Join(".", "cache", "images")
+	return filepath.Join(append([]string{root}, elem...)...)
 }
 
-// startPeriodicSave starts a background goroutine that saves pending images periodically.
+// startPeriodicSave starts a background goroutine that indexes the disk cache, then saves pending images
+// periodically and evicts the least recently used entries while the disk cache is over budget.
+// It is the only goroutine writing or evicting entries.
 func (cache *ImageCache) startPeriodicSave(interval time.Duration) {
 	cache.saveTimer = time.NewTicker(interval)
 	go func() {
+		defer close(cache.doneChan)
+
+		// Older versions stored re-encoded PNGs; migrated entries are indexed like the rest
+		cache.migrateLegacyEntries()
+		cache.loadIndex()
+		cache.evictOverBudget() // The budget may have been exceeded last session
 		for {
 			select {
 			case <-cache.saveTimer.C:
 				cache.FlushToDisk()
+				cache.persistAccessTimes()
+				cache.evictOverBudget()
 			case <-cache.stopChan:
 				cache.saveTimer.Stop()
+				cache.FlushToDisk()
+				cache.persistAccessTimes()
 				return
 			}
 		}
@@ -98,10 +132,11 @@ func (cache *ImageCache) startPeriodicSave(interval time.Duration) {
 // Call this when the application is closing.
 func (cache *ImageCache) Shutdown() {
 	close(cache.stopChan)
-	cache.FlushToDisk()
+	<-cache.doneChan
 }
 
 // FlushToDisk saves all pending images to disk.
+// Call only from the periodic save goroutine, or before it started.
 func (cache *ImageCache) FlushToDisk() {
 	cache.mutex.Lock()
 	if len(cache.pending) == 0 {
@@ -110,78 +145,99 @@ func (cache *ImageCache) FlushToDisk() {
 	}
 
 	// Copy pending map and clear it
-	imagesToSave := cache.pending
-	cache.pending = make(map[string]image.Image)
+	entriesToSave := cache.pending
+	cache.pending = make(map[string]imageEntry)
 	cache.mutex.Unlock()
 
 	// Save images outside the lock
-	for imageID, img := range imagesToSave {
-		cache.saveImageToDisk(imageID, img)
+	for imageID, entry := range entriesToSave {
+		size, err := cache.writeEntry(imageID, entry)
+		if err != nil {
+			println("Warning: Failed to save cached image:", err.Error())
+			continue
+		}
+		cache.recordWrite(imageID, size)
 	}
 }
 
-// saveImageToDisk writes a single image to the cache directory.
-func (cache *ImageCache) saveImageToDisk(imageID string, img image.Image) {
-	cachePath := filepath.Join(cache.cacheDir, imageID+".png")
-
-	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
-		return
+// Get retrieves an image from cache (memory first, then disk).
+func (cache *ImageCache) Get(imageID string) image.Image {
+	if img := cache.getMemory(imageID); img != nil {
+		return img
 	}
+	return cache.readDisk(imageID)
+}
 
-	file, err := os.Create(cachePath)
-	if err != nil {
-		return
+// getMemory returns an image decoded earlier, or nil.
+func (cache *ImageCache) getMemory(imageID string) image.Image {
+	if imageID == "" {
+		return nil
 	}
-	defer func() { _ = file.Close() }()
 
-	_ = png.Encode(file, img)
+	// A hit updates its recency, so this takes the write lock
+	cache.mutex.Lock()
+	img := cache.memory.get(imageID)
+	cache.mutex.Unlock()
+
+	if img != nil {
+		cache.recordAccess(imageID)
+	}
+	return img
 }
 
-// Get retrieves an image from cache (memory first, then disk).
-func (cache *ImageCache) Get(imageID string) image.Image {
+// readDisk decodes an image from the disk cache and keeps it in memory, or returns nil.
+func (cache *ImageCache) readDisk(imageID string) image.Image {
 	if imageID == "" {
 		return nil
 	}
 
-	// Check memory cache
-	cache.mutex.RLock()
-	if img, exists := cache.memory[imageID]; exists {
-		cache.mutex.RUnlock()
-		return img
-	}
-	cache.mutex.RUnlock()
-
-	// Check disk cache
-	cachePath := filepath.Join(cache.cacheDir, imageID+".png")
-	file, err := os.Open(cachePath)
+	data, err := cache.readData(imageID)
 	if err != nil {
 		return nil
 	}
-	defer func() { _ = file.Close() }()
 
-	img, _, err := image.Decode(file)
+	img, _, err := image.Decode(bytes.NewReader(data))
 	if err != nil {
 		return nil
 	}
+	cache.recordAccess(imageID)
 
 	// Store in memory for faster access
 	cache.mutex.Lock()
-	cache.memory[imageID] = img
+	cache.memory.add(imageID, img)
 	cache.mutex.Unlock()
 
 	return img
 }
 
-// Set stores an image in memory and marks it for later disk persistence.
-func (cache *ImageCache) Set(imageID string, img image.Image) {
-	if imageID == "" || img == nil {
-		return
+// Set decodes a downloaded image, stores it in memory and marks its original bytes for later disk persistence.
+// contentType may be empty; it is then detected from the bytes.
+func (cache *ImageCache) Set(imageID, url, contentType string, data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if imageID == "" {
+		return img, nil
+	}
+
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
 	}
 
 	cache.mutex.Lock()
-	cache.memory[imageID] = img
-	cache.pending[imageID] = img
+	cache.memory.add(imageID, img)
+	cache.pending[imageID] = imageEntry{
+		data: data,
+		meta: imageMeta{
+			ContentType: contentType,
+			URL:         url,
+			Size:        int64(len(data)),
+			FetchedAt:   time.Now(),
+		},
+	}
 	cache.mutex.Unlock()
+	return img, nil
 }
 
 // LoadFromURL loads an image from URL, using cache if available.
@@ -193,8 +249,17 @@ func (cache *ImageCache) LoadFromURL(imageID, url string) image.Image {
 	if img := cache.Get(imageID); img != nil {
 		return img
 	}
+	return cache.download(context.Background(), imageID, url)
+}
 
-	response, err := cache.client.Get(url)
+// download fetches an image and stores it in the cache. Cancelling ctx aborts the request.
+func (cache *ImageCache) download(ctx context.Context, imageID, url string) image.Image {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil
+	}
+
+	response, err := cache.client.Do(request)
 	if err != nil {
 		return nil
 	}
@@ -204,49 +269,38 @@ func (cache *ImageCache) LoadFromURL(imageID, url string) image.Image {
 		return nil
 	}
 
-	img, _, err := image.Decode(response.Body)
+	data, err := io.ReadAll(response.Body)
 	if err != nil {
 		return nil
 	}
 
-	cache.Set(imageID, img)
+	img, err := cache.Set(imageID, url, response.Header.Get("Content-Type"), data)
+	if err != nil {
+		return nil
+	}
 	return img
 }
 
-// LoadFromURLAsync loads an image asynchronously and calls onLoaded on the UI thread.
+// LoadFromURLAsync loads an image on the download workers and calls onLoaded on the UI thread.
+// Callers asking for the same image share one load. The load is unscoped and visible;
+// see ImageScope for loads that can be deprioritised or cancelled.
 func (cache *ImageCache) LoadFromURLAsync(imageID, url string, circular bool, onLoaded func(image.Image)) {
-	if url == "" {
-		return
-	}
-
-	// Check cache first (fast path)
-	if img := cache.Get(imageID); img != nil {
-		if circular {
-			img = circleClip(img)
-		}
-		onLoaded(img)
-		return
-	}
-
-	go func() {
-		img := cache.LoadFromURL(imageID, url)
-		if img == nil {
-			return
-		}
-
-		if circular {
-			img = circleClip(img)
-		}
+	cache.load(ImageOwner{}, imageID, url, PriorityVisible, circular, onLoaded)
+}
 
-		fyne.CurrentApp().Driver().DoFromGoroutine(func() {
-			onLoaded(img)
-		}, true)
-	}()
+// Prefetch loads an image into the cache in the background, after any image waited for on screen.
+func (cache *ImageCache) Prefetch(imageID, url string) {
+	cache.load(ImageOwner{}, imageID, url, PriorityPrefetch, false, nil)
 }
 
 // LoadImageToContainer loads an image and updates a container with it.
 func (cache *ImageCache) LoadImageToContainer(imageID, url string, size fyne.Size, target *fyne.Container, circular bool, background fyne.CanvasObject) {
-	cache.LoadFromURLAsync(imageID, url, circular, func(loadedImage image.Image) {
+	cache.loadImageToContainer(ImageOwner{}, imageID, url, size, target, circular, background)
+}
+
+// loadImageToContainer loads an image for an owner and updates a container with it.
+func (cache *ImageCache) loadImageToContainer(owner ImageOwner, imageID, url string, size fyne.Size, target *fyne.Container, circular bool, background fyne.CanvasObject) {
+	cache.load(owner, imageID, url, PriorityVisible, circular, func(loadedImage image.Image) {
 		img := canvas.NewImageFromImage(loadedImage)
 		img.FillMode = canvas.ImageFillContain
 		img.SetMinSize(size)
@@ -264,50 +318,38 @@ func (cache *ImageCache) LoadImageToContainer(imageID, url string, size fyne.Siz
 // ClearMemoryCache clears only the in-memory cache.
 func (cache *ImageCache) ClearMemoryCache() {
 	cache.mutex.Lock()
-	cache.memory = make(map[string]image.Image)
+	cache.memory.clear()
 	cache.mutex.Unlock()
 }
 
-// SetMaxCacheSize sets the maximum cache size in bytes.
-func (cache *ImageCache) SetMaxCacheSize(sizeBytes int64) {
+// SetMemoryBudgets sets how many bytes of decoded pixels are kept in memory for small images
+// (avatars, icons, emojis) and for large ones (attachments, banners). Images beyond them are evicted.
+func (cache *ImageCache) SetMemoryBudgets(small, large int64) {
 	cache.mutex.Lock()
-	cache.MaxCacheSizeBytes = sizeBytes
+	cache.memory.small.setBudget(small)
+	cache.memory.large.setBudget(large)
 	cache.mutex.Unlock()
 }
 
-// GetCacheSize returns the total size of the disk cache in bytes.
-func (cache *ImageCache) GetCacheSize() (int64, error) {
-	var totalSize int64
-
-	err := filepath.Walk(cache.cacheDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil // Skip files we can't access
-		}
-		if !info.IsDir() {
-			totalSize += info.Size()
-		}
-		return nil
-	})
-
-	return totalSize, err
+// MemoryStats returns the usage, budgets and hit rate of the in-memory cache.
+func (cache *ImageCache) MemoryStats() ImageMemoryStats {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	return cache.memory.stats()
 }
 
-// CheckAndPurgeCache checks if the cache exceeds the size maxMessages and purges it if necessary.
-func (cache *ImageCache) CheckAndPurgeCache() {
-	cacheSize, err := cache.GetCacheSize()
-	if err != nil {
-		println("Warning: Failed to check cache size:", err.Error())
-		return
-	}
-
-	cache.mutex.RLock()
-	maxSize := cache.MaxCacheSizeBytes
-	cache.mutex.RUnlock()
+// SetMaxCacheSize sets the maximum cache size in bytes. Entries over it are evicted on the next save.
+func (cache *ImageCache) SetMaxCacheSize(sizeBytes int64) {
+	cache.mutex.Lock()
+	cache.MaxCacheSizeBytes = sizeBytes
+	cache.mutex.Unlock()
+}
 
-	if cacheSize > maxSize {
-		println("Image cache exceeds size maxMessages, purging...")
-		cache.PurgeCache()
-	}
+// GetCacheSize returns the total size of the disk cache in bytes, as indexed.
+func (cache *ImageCache) GetCacheSize() int64 {
+	cache.indexMutex.Lock()
+	defer cache.indexMutex.Unlock()
+	return cache.index.total
 }
 
 // PurgeCache removes all files from the disk cache.
@@ -317,7 +359,7 @@ func (cache *ImageCache) PurgeCache() {
 
 	// Clear pending writes
 	cache.mutex.Lock()
-	cache.pending = make(map[string]image.Image)
+	cache.pending = make(map[string]imageEntry)
 	cache.mutex.Unlock()
 
 	// Remove all files from cache directory
@@ -333,6 +375,15 @@ func (cache *ImageCache) PurgeCache() {
 			println("Warning: Failed to remove cache entry:", err.Error())
 		}
 	}
+
+	// Index whatever could not be removed. The old entries are dropped first, or loadIndex would keep
+	// them as writes made meanwhile.
+	cache.indexMutex.Lock()
+	cache.index.entries = make(map[string]*diskEntry)
+	cache.index.total = 0
+	cache.index.touched = make(map[string]bool)
+	cache.indexMutex.Unlock()
+	cache.loadIndex()
 }
 
 // circleClip clips an image to a circle shape.
@@ -1,134 +1,672 @@
 package cache
 
 import (
+	"maps"
 	"slices"
 	"sync"
+	"sync/atomic"
 
 	"github.com/sentinelb51/revoltgo"
 )
 
+// storedLoadLimit is the number of messages LoadStored reads from disk for a channel.
+const storedLoadLimit = 100
+
 // MessageCache provides an in-memory cache for channel messages.
-// Messages are kept in memory for fast access.
+// Messages are kept in memory for fast access, and written through to a MessageStore when one is open.
+//
+// A channel's messages may have gaps (e.g. stored history older than the latest fetched page), so the
+// cache tracks the disjoint ranges known to be complete. The newest range is what the channel shows;
+// loading history before it fills the gap to the next older range, merging the two.
+//
+// The cache holds at most budget messages across all channels. When over budget, the least recently
+// accessed channels are trimmed (oldest messages first) or evicted. The current channel is never
+// trimmed, since history loading continues from its oldest message; it may exceed the budget alone
+// until another channel becomes current.
 type MessageCache struct {
-	mutex       sync.RWMutex
-	messages    map[string][]*revoltgo.Message // channelID → messages (sorted oldest to newest)
-	depleted    map[string]bool                // channelID -> history depleted
-	maxMessages int
-	maxChannels int
+	mutex      sync.RWMutex
+	messages   map[string][]*revoltgo.Message // channelID → messages (sorted oldest to newest)
+	ranges     map[string][]messageRange      // channelID → complete ranges (sorted oldest to newest)
+	depleted   map[string]bool                // channelID -> oldest range starts at the channel's first message
+	live       map[string]bool                // channelID → newest range reaches the latest message
+	deleted    map[string]string              // messageID → channelID, for messages deleted while cached
+	lastAccess map[string]*atomic.Uint64      // channelID → access clock value of the last access, for cached channels
+	clock      atomic.Uint64                  // Incremented on every access; readers update recency under RLock
+	current    string                         // Channel exempt from eviction
+	total      int                            // Messages across all channels
+	budget     int
+	store      *MessageStore // Disk store of the logged-in account, or nil
 }
 
-// NewMessageCache creates a new message cache with the specified maxMessages per channel.
-func NewMessageCache(limitPerChannel, maxChannels int) *MessageCache {
+// NewMessageCache creates a new message cache holding up to budget messages across channels.
+func NewMessageCache(budget int) *MessageCache {
 	return &MessageCache{
-		messages:    make(map[string][]*revoltgo.Message),
-		depleted:    make(map[string]bool),
-		maxMessages: limitPerChannel,
-		maxChannels: maxChannels,
+		messages:   make(map[string][]*revoltgo.Message),
+		ranges:     make(map[string][]messageRange),
+		depleted:   make(map[string]bool),
+		live:       make(map[string]bool),
+		deleted:    make(map[string]string),
+		lastAccess: make(map[string]*atomic.Uint64),
+		budget:     budget,
 	}
 }
 
-// enforceLimit enforces maxChannels and randomly evicts a channel from cache.
-// Call before adding the new channel, and with a Lock held.
-func (cache *MessageCache) enforceLimit(newChannelID string) {
+// OpenStore attaches the disk store of an account, keeping up to retention messages per channel.
+// A store of another account is closed first; reopening the current account's store does nothing.
+func (cache *MessageCache) OpenStore(accountID string, retention int) error {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if cache.store != nil {
+		if cache.store.AccountID() == accountID {
+			return nil
+		}
+		cache.store.Close()
+		cache.store = nil
+	}
 
-	// If channel already exists, ignore
-	if _, exists := cache.messages[newChannelID]; exists {
-		return
+	store, err := OpenMessageStore(accountID, retention)
+	if err != nil {
+		return err
+	}
+	cache.store = store
+	return nil
+}
+
+// CloseStore writes pending changes to disk and detaches the store.
+func (cache *MessageCache) CloseStore() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if cache.store != nil {
+		cache.store.Close()
+		cache.store = nil
+	}
+}
+
+// LoadStored fills an uncached channel from the disk store and returns its newest range (oldest to newest).
+// Channels already in memory are returned as they are. Stored messages may be outdated, so the channel
+// is not live until its latest messages are fetched. Reads from disk, so it may take a few milliseconds.
+func (cache *MessageCache) LoadStored(channelID string) []*revoltgo.Message {
+	cache.mutex.RLock()
+	store := cache.store
+	_, cached := cache.messages[channelID]
+	cache.mutex.RUnlock()
+
+	if cached {
+		return cache.Latest(channelID)
+	}
+	if store == nil {
+		return nil
+	}
+
+	stored, ranges := store.Load(channelID, storedLoadLimit)
+	if len(stored) == 0 {
+		return nil
+	}
+
+	// Messages appended while the channel was not cached are stored without a range. Nothing is known
+	// about what lies between them, so each is a range of its own rather than part of the history.
+	for _, message := range stored {
+		if !slices.ContainsFunc(ranges, func(r messageRange) bool { return r.contains(message.ID) }) {
+			ranges = addRange(ranges, messageRange{Oldest: message.ID, Newest: message.ID})
+		}
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	// Fetched or received while reading the disk; those are newer
+	if _, ok := cache.messages[channelID]; ok {
+		return cache.latest(channelID)
 	}
 
-	if len(cache.messages) < cache.maxChannels {
+	cache.replace(channelID, stored)
+	cache.ranges[channelID] = ranges
+	cache.touch(channelID)
+	cache.enforceBudget()
+	return cache.latest(channelID)
+}
+
+// SetCurrent marks the channel the user is viewing. It counts as an access if the channel is cached,
+// and is never evicted; the previously current channel becomes subject to the budget again.
+func (cache *MessageCache) SetCurrent(channelID string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.current = channelID
+	cache.touch(channelID)
+	cache.enforceBudget()
+}
+
+// Len returns the number of cached messages across all channels.
+func (cache *MessageCache) Len() int {
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+	return cache.total
+}
+
+// touch records an access to a cached channel; uncached channels are ignored. Call with a Lock held.
+func (cache *MessageCache) touch(channelID string) {
+	if _, ok := cache.messages[channelID]; !ok {
 		return
 	}
 
-	// Evict one random entry (iteration order is random)
-	for key := range cache.messages {
-		delete(cache.messages, key)
-		delete(cache.depleted, key)
-		break
+	access := cache.lastAccess[channelID]
+	if access == nil {
+		access = new(atomic.Uint64)
+		cache.lastAccess[channelID] = access
+	}
+	access.Store(cache.clock.Add(1))
+}
+
+// touchShared records an access to a cached channel. Call with an RLock held.
+func (cache *MessageCache) touchShared(channelID string) {
+	if access := cache.lastAccess[channelID]; access != nil {
+		access.Store(cache.clock.Add(1))
 	}
 }
 
-// IsDepleted returns true if the channel history is fully loaded.
+// replace stores a channel's messages and keeps the total in sync. Call with a Lock held.
+func (cache *MessageCache) replace(channelID string, messages []*revoltgo.Message) {
+	cache.total += len(messages) - len(cache.messages[channelID])
+	cache.messages[channelID] = messages
+}
+
+// replaceAt replaces the channel's message at index in a copy of its slice.
+// Callers of Get may still be iterating the old slice, so it is never written in place. Call with a Lock held.
+func (cache *MessageCache) replaceAt(channelID string, index int, message *revoltgo.Message) {
+	messages := slices.Clone(cache.messages[channelID])
+	messages[index] = message
+	cache.messages[channelID] = messages
+}
+
+// enforceBudget trims or evicts the least recently accessed channels until the cache is within budget.
+// Call with a Lock held.
+func (cache *MessageCache) enforceBudget() {
+	for cache.total > cache.budget {
+		victim := cache.leastRecentlyUsed()
+		if victim == "" {
+			return // Only the current channel is left
+		}
+
+		messages := cache.messages[victim]
+		excess := cache.total - cache.budget
+		if excess >= len(messages) {
+			cache.evict(victim)
+			continue
+		}
+
+		// Keep the newest messages; the trimmed history can be loaded again
+		cache.replace(victim, messages[excess:])
+		cache.ranges[victim] = clipRanges(cache.ranges[victim], messages[excess].ID)
+		cache.depleted[victim] = false
+	}
+}
+
+// leastRecentlyUsed returns the cached channel accessed longest ago, other than the current one.
+// Call with a Lock held.
+func (cache *MessageCache) leastRecentlyUsed() string {
+	var victim string
+	var oldest uint64
+	for channelID := range cache.messages {
+		if channelID == cache.current {
+			continue
+		}
+		var access uint64
+		if lastAccess := cache.lastAccess[channelID]; lastAccess != nil {
+			access = lastAccess.Load()
+		}
+		if victim == "" || access < oldest {
+			victim, oldest = channelID, access
+		}
+	}
+	return victim
+}
+
+// evict removes a channel from memory. Call with a Lock held.
+func (cache *MessageCache) evict(channelID string) {
+	cache.total -= len(cache.messages[channelID])
+	delete(cache.messages, channelID)
+	delete(cache.ranges, channelID)
+	delete(cache.depleted, channelID)
+	delete(cache.live, channelID)
+	delete(cache.lastAccess, channelID)
+
+	for messageID, deletedFrom := range cache.deleted {
+		if deletedFrom == channelID {
+			delete(cache.deleted, messageID)
+		}
+	}
+}
+
+// IsDepleted returns true if the channel's newest range reaches back to its first message,
+// so there is no older history or gap left to load.
 func (cache *MessageCache) IsDepleted(channelID string) bool {
 	cache.mutex.RLock()
 	defer cache.mutex.RUnlock()
-	return cache.depleted[channelID]
+	return cache.depleted[channelID] && len(cache.ranges[channelID]) <= 1
 }
 
-// SetDepleted marks the channel history as depleted or not.
+// SetDepleted marks whether the channel has no messages before its newest range.
+// Older ranges are dropped when depleted: nothing exists before the newest range, so they were deleted.
 func (cache *MessageCache) SetDepleted(channelID string, depleted bool) {
 	cache.mutex.Lock()
 	defer cache.mutex.Unlock()
+
 	cache.depleted[channelID] = depleted
+	ranges := cache.ranges[channelID]
+	if !depleted || len(ranges) <= 1 {
+		return
+	}
+
+	newest := ranges[len(ranges)-1]
+	messages := cache.messages[channelID]
+	index := indexFrom(messages, newest.Oldest)
+	cache.forget(channelID, messages[:index])
+	cache.replace(channelID, messages[index:])
+	cache.ranges[channelID] = []messageRange{newest}
+}
+
+// IsLive returns true if the channel's newest cached messages are up to date.
+// Stored history is not live until the latest messages are fetched.
+func (cache *MessageCache) IsLive(channelID string) bool {
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+	return cache.live[channelID]
 }
 
-// Get returns messages for a channel from memory.
+// MarkStale marks every cached channel as no longer live, e.g. when the gateway connection drops and
+// new messages stop arriving. Messages appended meanwhile start ranges of their own.
+func (cache *MessageCache) MarkStale() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	clear(cache.live)
+}
+
+// NewestIDs returns the ID of the newest cached message of every cached channel: channelID → messageID.
+func (cache *MessageCache) NewestIDs() map[string]string {
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+
+	newest := make(map[string]string, len(cache.messages))
+	for channelID, messages := range cache.messages {
+		if len(messages) > 0 {
+			newest[channelID] = messages[len(messages)-1].ID
+		}
+	}
+	return newest
+}
+
+// Extend adds messages fetched after afterID, a cached message, sorted oldest to newest.
+// The range containing afterID grows to the newest of them. A channel is live again once caughtUp,
+// i.e. the fetch reached its latest message. Uncached channels are left alone.
+func (cache *MessageCache) Extend(channelID, afterID string, messages []*revoltgo.Message, caughtUp bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if _, ok := cache.messages[channelID]; !ok {
+		return
+	}
+
+	if len(messages) > 0 {
+		cache.fill(channelID, messages, messageRange{Oldest: afterID, Newest: messages[len(messages)-1].ID})
+	}
+	if caughtUp {
+		cache.live[channelID] = true
+	}
+	cache.enforceBudget()
+}
+
+// Get returns all cached messages of a channel, including older ranges, from memory.
+// Counts as an access if the channel is cached.
 func (cache *MessageCache) Get(channelID string) []*revoltgo.Message {
 	cache.mutex.RLock()
 	defer cache.mutex.RUnlock()
+
+	cache.touchShared(channelID)
 	return cache.messages[channelID]
 }
 
-// Set stores messages for a channel (replaces existing).
+// Contains reports whether a message is cached in memory. Does not count as an access.
+func (cache *MessageCache) Contains(channelID, messageID string) bool {
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+
+	messages := cache.messages[channelID]
+	index := indexFrom(messages, messageID)
+	return index < len(messages) && messages[index].ID == messageID
+}
+
+// Latest returns the messages of a channel's newest range: the ones shown without a gap between them.
+// Counts as an access if the channel is cached.
+func (cache *MessageCache) Latest(channelID string) []*revoltgo.Message {
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+
+	cache.touchShared(channelID)
+	return cache.latest(channelID)
+}
+
+// latest returns the messages of a channel's newest range. Call with a Lock held.
+func (cache *MessageCache) latest(channelID string) []*revoltgo.Message {
+	messages := cache.messages[channelID]
+	ranges := cache.ranges[channelID]
+	if len(ranges) == 0 {
+		return messages
+	}
+	return messages[indexFrom(messages, ranges[len(ranges)-1].Oldest):]
+}
+
+// Set stores the latest messages of a channel, which become its newest range.
+// Cached messages they do not reach stay as older ranges, with a gap before the new ones.
 // Reverses API response (newest→oldest) to store as oldest→newest.
 func (cache *MessageCache) Set(cID string, messages []*revoltgo.Message) {
 	cache.mutex.Lock()
 	defer cache.mutex.Unlock()
 
-	cache.enforceLimit(cID)
-
 	// Reverse in place: API returns newest→oldest, store oldest→newest
 	slices.Reverse(messages)
+	if len(messages) == 0 {
+		return
+	}
+
+	cache.fill(cID, messages, spanOf(messages))
+	cache.live[cID] = true
+	cache.touch(cID)
+	cache.enforceBudget()
+}
+
+// Prepend adds history fetched before beforeID, the oldest message of the channel's newest range.
+// If the fetched messages reach an older range, the gap is filled and the ranges merge.
+// Returns the messages that now precede beforeID in the newest range (oldest to newest), including
+// those of a merged range, or nil if beforeID is no longer in the newest range.
+func (cache *MessageCache) Prepend(cID, beforeID string, messages []*revoltgo.Message) []*revoltgo.Message {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	// API returns newest→oldest; store oldest→newest
+	slices.Reverse(messages)
+	if len(messages) == 0 {
+		return nil
+	}
+
+	// Switched away and evicted meanwhile; a range without its newer messages would hide the gap
+	if _, ok := cache.messages[cID]; !ok {
+		cache.persist(cID, nil, messages...)
+		return nil
+	}
+
+	// Everything between the oldest fetched message and beforeID is known now
+	cache.fill(cID, messages, messageRange{Oldest: messages[0].ID, Newest: beforeID})
+	cache.touch(cID)
+	cache.enforceBudget()
 
-	// Trim oldest (front) if over maxMessages
-	if len(messages) > cache.maxMessages {
-		messages = messages[len(messages)-cache.maxMessages:]
+	ranges := cache.ranges[cID]
+	if len(ranges) == 0 || !ranges[len(ranges)-1].contains(beforeID) {
+		return nil
 	}
-	cache.messages[cID] = messages
+
+	all := cache.messages[cID]
+	return all[indexFrom(all, ranges[len(ranges)-1].Oldest):indexFrom(all, beforeID)]
 }
 
-// Prepend adds messages to the beginning of the channel's cache.
-// Used for loading history.
-func (cache *MessageCache) Prepend(cID string, messages []*revoltgo.Message) {
+// Insert adds a contiguous window of messages, such as the history around a jumped-to message,
+// sorted oldest to newest. It becomes its own range unless it reaches a cached one.
+// Uncached channels are left alone, like in Append.
+func (cache *MessageCache) Insert(channelID string, messages []*revoltgo.Message) {
 	cache.mutex.Lock()
 	defer cache.mutex.Unlock()
 
-	cache.enforceLimit(cID)
+	if len(messages) == 0 {
+		return
+	}
+	if _, ok := cache.messages[channelID]; !ok {
+		return
+	}
 
-	// API returns newest→oldest.
-	// We want to prepend them such that they remain chronologically before the existing ones.
-	// existing: [M100, M101, M102]
-	// new (API): [M99, M98]
-	// reversed new: [M98, M99]
-	// result: [M98, M99, M100, ... ]
-	slices.Reverse(messages)
+	cache.fill(channelID, messages, spanOf(messages))
+	cache.enforceBudget()
+}
+
+// fill merges fetched messages (oldest to newest) into a channel and marks covered as complete.
+// Cached messages within covered that were not fetched have since been deleted and are dropped;
+// the bounds of covered may be cached messages the fetch started from, so they are kept. Call with a Lock held.
+func (cache *MessageCache) fill(channelID string, fetched []*revoltgo.Message, covered messageRange) {
+	fetchedIDs := make(map[string]bool, len(fetched))
+	for _, m := range fetched {
+		fetchedIDs[m.ID] = true
+	}
 
-	current := cache.messages[cID]
-	cache.messages[cID] = append(messages, current...)
-	// We do not trim on prepend to allow history browsing
+	cached := cache.messages[channelID]
+	kept := make([]*revoltgo.Message, 0, len(cached))
+	var stale []*revoltgo.Message
+	for _, m := range cached {
+		inside := covered.contains(m.ID) && m.ID != covered.Oldest && m.ID != covered.Newest
+		if inside && !fetchedIDs[m.ID] {
+			stale = append(stale, m)
+			continue
+		}
+		kept = append(kept, m)
+	}
+
+	cache.forget(channelID, stale)
+	cache.replace(channelID, mergeMessages(kept, fetched))
+	cache.ranges[channelID] = addRange(cache.ranges[channelID], covered)
+	cache.persist(channelID, &covered, fetched...)
+}
+
+// forget removes dropped messages from the disk store. Call with a Lock held.
+func (cache *MessageCache) forget(channelID string, messages []*revoltgo.Message) {
+	if cache.store == nil {
+		return
+	}
+	for _, m := range messages {
+		cache.store.Delete(channelID, m.ID)
+	}
 }
 
 // Append adds a new message to end of channel's cache.
 // O(1) amortized - Go slices grow efficiently.
-func (cache *MessageCache) Append(channelID string, message *revoltgo.Message) {
+// On a live channel the message extends the newest range; otherwise its position relative to the
+// cached messages is unknown and it starts a range of its own.
+// Uncached channels only get the message on disk: a lone message in memory would pass for the
+// channel's history and skip loading it. Appending does not count as an access.
+// A message already cached (e.g. our own, added before its gateway echo) replaces the cached copy;
+// Append then returns false.
+func (cache *MessageCache) Append(channelID string, message *revoltgo.Message) bool {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	messages, ok := cache.messages[channelID]
+	if !ok {
+		cache.persist(channelID, nil, message)
+		return true
+	}
+
+	index := indexFrom(messages, message.ID)
+	if index < len(messages) && messages[index].ID == message.ID {
+		cache.replaceAt(channelID, index, message)
+		cache.persist(channelID, nil, message)
+		return false
+	}
+
+	span := messageRange{Oldest: message.ID, Newest: message.ID}
+	if ranges := cache.ranges[channelID]; cache.live[channelID] && len(ranges) > 0 {
+		span.Oldest = ranges[len(ranges)-1].Oldest
+	}
+
+	if index < len(messages) {
+		// Older than a cached message; keep the slice sorted
+		messages = mergeMessages(messages, []*revoltgo.Message{message})
+	} else {
+		messages = append(messages, message)
+	}
+	cache.replace(channelID, messages)
+	cache.ranges[channelID] = addRange(cache.ranges[channelID], span)
+	cache.persist(channelID, &span, message)
+	cache.enforceBudget()
+	return true
+}
+
+// Update replaces a cached message with the same ID.
+// Returns false if the message is not cached.
+func (cache *MessageCache) Update(channelID string, message *revoltgo.Message) bool {
 	cache.mutex.Lock()
 	defer cache.mutex.Unlock()
 
-	cache.enforceLimit(channelID)
+	messages := cache.messages[channelID]
+	for i, m := range messages {
+		if m.ID == message.ID {
+			cache.replaceAt(channelID, i, message)
+			cache.persist(channelID, nil, message)
+			return true
+		}
+	}
+	return false
+}
+
+// Merge applies the non-empty fields of a partial message to the cached message with the given ID.
+// The cached entry is replaced by a merged copy. Returns nil if the message is not cached.
+// Fields the server clears (such as Pinned) are empty in the partial, so they are set with SetPinned
+// or by replacing the message with a fetched copy.
+func (cache *MessageCache) Merge(channelID, messageID string, partial *revoltgo.Message) *revoltgo.Message {
+	return cache.modify(channelID, messageID, func(message *revoltgo.Message) {
+		mergeMessage(message, partial)
+	})
+}
+
+// mergeMessage applies the non-empty fields of a message update to a message.
+func mergeMessage(message, partial *revoltgo.Message) {
+	if partial.Content != "" {
+		message.Content = partial.Content
+	}
+	if partial.Edited != nil {
+		message.Edited = partial.Edited
+	}
+	if partial.Embeds != nil {
+		message.Embeds = partial.Embeds
+	}
+	if partial.Reactions != nil {
+		message.Reactions = partial.Reactions
+	}
+	if partial.Interactions != nil {
+		message.Interactions = partial.Interactions
+	}
+	if partial.Pinned {
+		message.Pinned = true
+	}
+}
+
+// SetPinned pins or unpins the cached message with the given ID.
+// Returns the updated message, or nil if the message is not cached.
+func (cache *MessageCache) SetPinned(channelID, messageID string, pinned bool) *revoltgo.Message {
+	return cache.modify(channelID, messageID, func(message *revoltgo.Message) {
+		message.Pinned = pinned
+	})
+}
+
+// AddReaction records a user's reaction on a cached message.
+// Returns the updated message, or nil if the message is not cached.
+func (cache *MessageCache) AddReaction(channelID, messageID, emojiID, userID string) *revoltgo.Message {
+	return cache.modify(channelID, messageID, func(message *revoltgo.Message) {
+		if slices.Contains(message.Reactions[emojiID], userID) {
+			return
+		}
+
+		reactions := maps.Clone(message.Reactions)
+		if reactions == nil {
+			reactions = make(map[string][]string)
+		}
+		reactions[emojiID] = append(slices.Clip(reactions[emojiID]), userID)
+		message.Reactions = reactions
+	})
+}
+
+// RemoveReaction removes a user's reaction from a cached message.
+// Returns the updated message, or nil if the message is not cached.
+func (cache *MessageCache) RemoveReaction(channelID, messageID, emojiID, userID string) *revoltgo.Message {
+	return cache.modify(channelID, messageID, func(message *revoltgo.Message) {
+		index := slices.Index(message.Reactions[emojiID], userID)
+		if index < 0 {
+			return
+		}
+
+		reactions := maps.Clone(message.Reactions)
+		users := slices.Delete(slices.Clone(reactions[emojiID]), index, index+1)
+		if len(users) == 0 {
+			delete(reactions, emojiID)
+		} else {
+			reactions[emojiID] = users
+		}
+		message.Reactions = reactions
+	})
+}
+
+// modify replaces a cached message with a modified shallow copy.
+// Widgets keep pointers to the old message, so it is never mutated in place.
+func (cache *MessageCache) modify(channelID, messageID string, apply func(*revoltgo.Message)) *revoltgo.Message {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
 
 	messages := cache.messages[channelID]
-	messages = append(messages, message)
+	for i, m := range messages {
+		if m.ID == messageID {
+			updated := *m
+			apply(&updated)
+			cache.replaceAt(channelID, i, &updated)
+			cache.persist(channelID, nil, &updated)
+			return &updated
+		}
+	}
+	return nil
+}
+
+// Remove deletes a message from the channel's cache and, if the channel is cached, remembers it
+// as deleted until the channel is evicted. Returns false if the message was not cached.
+func (cache *MessageCache) Remove(channelID, messageID string) bool {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
 
-	// Trim oldest (front) if over maxMessages
-	if len(messages) > cache.maxMessages {
-		messages = messages[1:]
+	if _, ok := cache.messages[channelID]; ok {
+		cache.deleted[messageID] = channelID
+	}
+	if cache.store != nil {
+		cache.store.Delete(channelID, messageID)
+	}
+
+	messages := cache.messages[channelID]
+	for i, m := range messages {
+		if m.ID == messageID {
+			// Copy instead of slices.Delete; callers of Get may still be iterating the old slice
+			remaining := make([]*revoltgo.Message, 0, len(messages)-1)
+			remaining = append(remaining, messages[:i]...)
+			cache.replace(channelID, append(remaining, messages[i+1:]...))
+			return true
+		}
+	}
+	return false
+}
+
+// persist writes messages, and the range they complete if any, through to the disk store, if one is open.
+// Call with a Lock held.
+func (cache *MessageCache) persist(channelID string, span *messageRange, messages ...*revoltgo.Message) {
+	if cache.store != nil {
+		cache.store.Put(channelID, span, messages...)
 	}
-	cache.messages[channelID] = messages
+}
+
+// IsDeleted returns true if the message was removed through Remove while its channel is cached.
+func (cache *MessageCache) IsDeleted(messageID string) bool {
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+	_, ok := cache.deleted[messageID]
+	return ok
 }
 
 // Clear removes all messages for a channel.
 func (cache *MessageCache) Clear(channelID string) {
 	cache.mutex.Lock()
 	defer cache.mutex.Unlock()
-	delete(cache.messages, channelID)
+	cache.evict(channelID)
 }
@@ -0,0 +1,372 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/sentinelb51/revoltgo"
+)
+
+// apiMessages returns n messages of a channel in API order (newest first), with sortable IDs.
+func apiMessages(channelID string, n int) []*revoltgo.Message {
+	return apiRange(channelID, 0, n)
+}
+
+// apiRange returns the channel's messages first to first+n-1 in API order (newest first).
+func apiRange(channelID string, first, n int) []*revoltgo.Message {
+	messages := make([]*revoltgo.Message, n)
+	for i := range messages {
+		messages[n-1-i] = &revoltgo.Message{ID: fmt.Sprintf("%s-%03d", channelID, first+i), Channel: channelID}
+	}
+	return messages
+}
+
+// messageIDs returns the IDs of messages in order.
+func messageIDs(messages []*revoltgo.Message) []string {
+	ids := make([]string, len(messages))
+	for i, m := range messages {
+		ids[i] = m.ID
+	}
+	return ids
+}
+
+// cachedChannels returns the cached channel IDs in sorted order.
+func cachedChannels(cache *MessageCache) []string {
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+
+	var channels []string
+	for channelID := range cache.messages {
+		channels = append(channels, channelID)
+	}
+	slices.Sort(channels)
+	return channels
+}
+
+func TestEvictsLeastRecentlyUsedChannel(t *testing.T) {
+	cache := NewMessageCache(30)
+	cache.Set("a", apiMessages("a", 10))
+	cache.Set("b", apiMessages("b", 10))
+	cache.Set("c", apiMessages("c", 10))
+
+	// Reading "a" makes "b" the least recently used
+	cache.Get("a")
+	cache.Set("d", apiMessages("d", 10))
+
+	if got, want := cachedChannels(cache), []string{"a", "c", "d"}; !slices.Equal(got, want) {
+		t.Fatalf("cached channels = %v, want %v", got, want)
+	}
+	if got := cache.Len(); got != 30 {
+		t.Fatalf("Len() = %d, want 30", got)
+	}
+}
+
+func TestEvictionFollowsAccessOrder(t *testing.T) {
+	cache := NewMessageCache(40)
+	for _, channelID := range []string{"a", "b", "c", "d"} {
+		cache.Set(channelID, apiMessages(channelID, 10))
+	}
+
+	// Access order, oldest first: c, a, d, b
+	cache.Get("c")
+	cache.Get("a")
+	cache.Get("d")
+	cache.Get("b")
+
+	var evicted []string
+	for i, channelID := range []string{"e", "f", "g"} {
+		before := cachedChannels(cache)
+		cache.Set(channelID, apiMessages(channelID, 10))
+		after := cachedChannels(cache)
+
+		for _, id := range before {
+			if !slices.Contains(after, id) {
+				evicted = append(evicted, id)
+			}
+		}
+		if len(evicted) != i+1 {
+			t.Fatalf("after adding %q: evicted %v, want one more channel", channelID, evicted)
+		}
+	}
+
+	if want := []string{"c", "a", "d"}; !slices.Equal(evicted, want) {
+		t.Fatalf("eviction order = %v, want %v", evicted, want)
+	}
+}
+
+func TestEvictionForgetsDeletedMessages(t *testing.T) {
+	cache := NewMessageCache(20)
+	cache.Set("a", apiMessages("a", 10))
+	cache.Remove("a", "a-003")
+	cache.Remove("z", "z-001") // Not cached: nothing to remember
+
+	if !cache.IsDeleted("a-003") || cache.IsDeleted("z-001") {
+		t.Fatalf("IsDeleted(a-003, z-001) = %v, %v, want true, false", cache.IsDeleted("a-003"), cache.IsDeleted("z-001"))
+	}
+
+	// Filling the budget evicts "a", and its deletions with it
+	cache.Set("b", apiMessages("b", 10))
+	cache.Set("c", apiMessages("c", 10))
+	if cache.IsDeleted("a-003") {
+		t.Fatal("deletion of evicted channel still remembered")
+	}
+}
+
+func TestCurrentChannelIsNeverEvicted(t *testing.T) {
+	cache := NewMessageCache(20)
+	cache.Set("current", apiRange("current", 30, 10))
+	cache.SetCurrent("current")
+	cache.Set("other", apiMessages("other", 10))
+
+	// "current" is the least recently used, but exempt
+	cache.Set("new", apiMessages("new", 10))
+	if got, want := cachedChannels(cache), []string{"current", "new"}; !slices.Equal(got, want) {
+		t.Fatalf("cached channels = %v, want %v", got, want)
+	}
+
+	// Scrollback may take the current channel over budget on its own
+	cache.Prepend("current", "current-030", apiRange("current", 0, 30))
+	if got, want := cachedChannels(cache), []string{"current"}; !slices.Equal(got, want) {
+		t.Fatalf("cached channels = %v, want %v", got, want)
+	}
+	if got := len(cache.Get("current")); got != 40 {
+		t.Fatalf("current channel has %d messages, want 40 (never trimmed)", got)
+	}
+
+	// Once another channel is current, the old one is trimmed back to budget
+	cache.SetCurrent("next")
+	if got := len(cache.Get("current")); got != 20 {
+		t.Fatalf("previous channel has %d messages, want 20", got)
+	}
+}
+
+func TestTrimKeepsNewestMessages(t *testing.T) {
+	cache := NewMessageCache(25)
+	cache.Set("a", apiMessages("a", 20))
+	cache.SetDepleted("a", true)
+	cache.Set("b", apiMessages("b", 10))
+
+	messages := cache.Get("a")
+	if len(messages) != 15 {
+		t.Fatalf("channel a has %d messages, want 15", len(messages))
+	}
+	if got, want := messages[0].ID, "a-005"; got != want {
+		t.Fatalf("oldest kept message = %s, want %s", got, want)
+	}
+	if got, want := messages[len(messages)-1].ID, "a-019"; got != want {
+		t.Fatalf("newest kept message = %s, want %s", got, want)
+	}
+	if cache.IsDepleted("a") {
+		t.Fatal("trimmed channel still marked depleted")
+	}
+}
+
+func TestAppendDoesNotCountAsAccess(t *testing.T) {
+	cache := NewMessageCache(20)
+	cache.Set("a", apiMessages("a", 10))
+	cache.Set("b", apiMessages("b", 10))
+
+	// New messages in "a" arrive, but the user last looked at "b"
+	cache.Append("a", &revoltgo.Message{ID: "a-100", Channel: "a"})
+
+	if got, want := cachedChannels(cache), []string{"a", "b"}; !slices.Equal(got, want) {
+		t.Fatalf("cached channels = %v, want %v", got, want)
+	}
+	if got := len(cache.Get("a")); got != 10 {
+		t.Fatalf("channel a has %d messages, want 10 (trimmed to budget)", got)
+	}
+}
+
+func TestAppendSkipsUncachedChannel(t *testing.T) {
+	cache := NewMessageCache(20)
+	cache.Append("a", &revoltgo.Message{ID: "a-000", Channel: "a"})
+
+	if messages := cache.Get("a"); messages != nil {
+		t.Fatalf("uncached channel has %d messages in memory, want none", len(messages))
+	}
+}
+
+func TestAppendIsIdempotent(t *testing.T) {
+	cache := NewMessageCache(20)
+	cache.Set("a", apiMessages("a", 3))
+
+	// The server's copy of a sent message, then its late gateway echo
+	if !cache.Append("a", &revoltgo.Message{ID: "a-100", Channel: "a"}) {
+		t.Fatal("Append() = false for a new message")
+	}
+	echo := &revoltgo.Message{ID: "a-100", Channel: "a", Content: "echo"}
+	if cache.Append("a", echo) {
+		t.Fatal("Append() = true for a cached message")
+	}
+	// A message older than the newest one cached
+	cache.Append("a", &revoltgo.Message{ID: "a-001a", Channel: "a"})
+
+	want := []string{"a-000", "a-001", "a-001a", "a-002", "a-100"}
+	if got := messageIDs(cache.Get("a")); !slices.Equal(got, want) {
+		t.Fatalf("messages = %v, want %v", got, want)
+	}
+	if !cache.Contains("a", "a-100") || cache.Get("a")[4] != echo {
+		t.Fatal("cached copy not replaced by the echo")
+	}
+}
+
+func TestGapIsNotDepleted(t *testing.T) {
+	cache := NewMessageCache(100)
+	cache.Set("a", apiRange("a", 0, 10))
+	cache.SetDepleted("a", true)
+
+	// A later fetch does not reach the cached messages, leaving a gap
+	cache.Set("a", apiRange("a", 50, 10))
+
+	if cache.IsDepleted("a") {
+		t.Fatal("channel with a gap marked depleted")
+	}
+	if got := len(cache.Get("a")); got != 20 {
+		t.Fatalf("channel has %d messages, want 20", got)
+	}
+	if got := messageIDs(cache.Latest("a")); got[0] != "a-050" || len(got) != 10 {
+		t.Fatalf("Latest() = %v, want a-050 to a-059", got)
+	}
+}
+
+func TestPrependFillsGap(t *testing.T) {
+	cache := NewMessageCache(100)
+	cache.Set("a", apiRange("a", 0, 10))
+	cache.SetDepleted("a", true)
+	cache.Set("a", apiRange("a", 50, 10))
+
+	// History before the newest range reaches the older one
+	visible := cache.Prepend("a", "a-050", apiRange("a", 5, 45))
+
+	if got := len(visible); got != 50 {
+		t.Fatalf("Prepend() returned %d messages, want 50", got)
+	}
+	if got, want := visible[0].ID, "a-000"; got != want {
+		t.Fatalf("oldest returned message = %s, want %s (from the merged range)", got, want)
+	}
+	if got, want := visible[len(visible)-1].ID, "a-049"; got != want {
+		t.Fatalf("newest returned message = %s, want %s", got, want)
+	}
+	if !cache.IsDepleted("a") {
+		t.Fatal("merged channel not depleted, but its oldest range starts the channel")
+	}
+	if got := len(cache.Latest("a")); got != 60 {
+		t.Fatalf("Latest() has %d messages, want 60", got)
+	}
+}
+
+func TestPrependStopsAtGap(t *testing.T) {
+	cache := NewMessageCache(100)
+	cache.Set("a", apiRange("a", 0, 10))
+	cache.Set("a", apiRange("a", 50, 10))
+
+	visible := cache.Prepend("a", "a-050", apiRange("a", 30, 20))
+
+	if got, want := messageIDs(visible)[0], "a-030"; got != want {
+		t.Fatalf("oldest returned message = %s, want %s", got, want)
+	}
+	if got := len(cache.Latest("a")); got != 30 {
+		t.Fatalf("Latest() has %d messages, want 30", got)
+	}
+	if got := len(cache.Get("a")); got != 40 {
+		t.Fatalf("channel has %d messages, want 40", got)
+	}
+}
+
+func TestFillDropsMessagesDeletedInRange(t *testing.T) {
+	cache := NewMessageCache(100)
+	cache.Set("a", apiRange("a", 0, 10))
+
+	// a-005 was deleted while the client was offline
+	fetched := slices.DeleteFunc(apiRange("a", 0, 12), func(m *revoltgo.Message) bool {
+		return m.ID == "a-005"
+	})
+	cache.Set("a", fetched)
+
+	if slices.Contains(messageIDs(cache.Get("a")), "a-005") {
+		t.Fatal("message missing from a fetch of its range was kept")
+	}
+	if got := len(cache.Get("a")); got != 11 {
+		t.Fatalf("channel has %d messages, want 11", got)
+	}
+}
+
+func TestDepletedDropsOlderRanges(t *testing.T) {
+	cache := NewMessageCache(100)
+	cache.Set("a", apiRange("a", 0, 10))
+	cache.Set("a", apiRange("a", 50, 10))
+
+	// Nothing precedes the newest range, so the older messages were deleted
+	cache.SetDepleted("a", true)
+
+	if !cache.IsDepleted("a") {
+		t.Fatal("channel not depleted")
+	}
+	if got := len(cache.Get("a")); got != 10 {
+		t.Fatalf("channel has %d messages, want 10", got)
+	}
+}
+
+func TestExtendCatchesUpAfterReconnect(t *testing.T) {
+	cache := NewMessageCache(100)
+	cache.Set("a", apiRange("a", 0, 10))
+	cache.MarkStale()
+
+	// A message arrives before the missed ones are fetched; its position is unknown
+	cache.Append("a", &revoltgo.Message{ID: "a-020", Channel: "a"})
+	if got := messageIDs(cache.Latest("a")); !slices.Equal(got, []string{"a-020"}) {
+		t.Fatalf("Latest() = %v, want only the appended message", got)
+	}
+
+	missed := apiRange("a", 10, 11) // a-010 to a-020
+	slices.Reverse(missed)
+	cache.Extend("a", "a-009", missed, true)
+
+	if !cache.IsLive("a") {
+		t.Fatal("channel not live after catching up")
+	}
+	if got := len(cache.Latest("a")); got != 21 {
+		t.Fatalf("Latest() has %d messages, want 21 (one range)", got)
+	}
+}
+
+func TestStoredLoneMessagesAreNotHistory(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	cache := NewMessageCache(20)
+	if err := cache.OpenStore("account", 100); err != nil {
+		t.Fatal(err)
+	}
+
+	// Received while the channel was never opened: only written to disk, with nothing between them known
+	cache.Append("a", &revoltgo.Message{ID: "a-001", Channel: "a"})
+	cache.Append("a", &revoltgo.Message{ID: "a-005", Channel: "a"})
+	cache.CloseStore()
+	if err := cache.OpenStore("account", 100); err != nil {
+		t.Fatal(err)
+	}
+	defer cache.CloseStore()
+
+	if got := messageIDs(cache.LoadStored("a")); !slices.Equal(got, []string{"a-005"}) {
+		t.Fatalf("LoadStored() = %v, want only the newest lone message", got)
+	}
+}
+
+func TestMergeLeavesReturnedSliceUnchanged(t *testing.T) {
+	cache := NewMessageCache(20)
+	cache.Set("a", apiMessages("a", 3))
+
+	// A caller may still be iterating what Get returned
+	held := cache.Get("a")
+	original := held[1]
+	cache.Merge("a", "a-001", &revoltgo.Message{Content: "edited"})
+
+	if held[1] != original {
+		t.Fatal("Merge wrote into a slice returned by Get")
+	}
+	if got := cache.Get("a")[1].Content; got != "edited" {
+		t.Fatalf("merged content = %q, want %q", got, "edited")
+	}
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sentinelb51/revoltgo"
+)
+
+// Profile is the lazily fetched part of a user's profile.
+// Presence and names are read live from the session state instead.
+type Profile struct {
+	User          *revoltgo.User // Only set if the user was not in the session state
+	Bio           string
+	Background    *revoltgo.Attachment // Profile banner
+	MutualServers []string             // Server IDs shared with us
+	FetchedAt     time.Time
+}
+
+// profileCall is a fetch in progress; concurrent loads of the same user wait on it.
+type profileCall struct {
+	done    chan struct{}
+	profile *Profile
+	err     error
+}
+
+// ProfileCache caches user profiles for a limited time and deduplicates concurrent fetches.
+type ProfileCache struct {
+	mutex    sync.Mutex
+	profiles map[string]*Profile     // userID → profile
+	inflight map[string]*profileCall // userID → fetch in progress
+	ttl      time.Duration
+}
+
+// NewProfileCache creates a profile cache whose entries expire after ttl.
+func NewProfileCache(ttl time.Duration) *ProfileCache {
+	return &ProfileCache{
+		profiles: make(map[string]*Profile),
+		inflight: make(map[string]*profileCall),
+		ttl:      ttl,
+	}
+}
+
+// Get returns a cached profile, or nil if it is missing or expired.
+func (cache *ProfileCache) Get(userID string) *Profile {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	profile := cache.profiles[userID]
+	if profile == nil || time.Since(profile.FetchedAt) > cache.ttl {
+		return nil
+	}
+	return profile
+}
+
+// Load returns the cached profile or calls fetch to get it. Blocks; call from a goroutine.
+// Concurrent loads of the same user share a single fetch.
+func (cache *ProfileCache) Load(userID string, fetch func() (*Profile, error)) (*Profile, error) {
+	if profile := cache.Get(userID); profile != nil {
+		return profile, nil
+	}
+
+	cache.mutex.Lock()
+	if call, exists := cache.inflight[userID]; exists {
+		cache.mutex.Unlock()
+		<-call.done
+		return call.profile, call.err
+	}
+
+	call := &profileCall{done: make(chan struct{})}
+	cache.inflight[userID] = call
+	cache.mutex.Unlock()
+
+	call.profile, call.err = fetch()
+
+	cache.mutex.Lock()
+	delete(cache.inflight, userID)
+	if call.err == nil && call.profile != nil {
+		cache.removeExpired()
+		call.profile.FetchedAt = time.Now()
+		cache.profiles[userID] = call.profile
+	}
+	cache.mutex.Unlock()
+
+	close(call.done)
+	return call.profile, call.err
+}
+
+// removeExpired drops the profiles older than the TTL, so users seen once are not kept forever.
+// Call with mutex held.
+func (cache *ProfileCache) removeExpired() {
+	for userID, profile := range cache.profiles {
+		if time.Since(profile.FetchedAt) > cache.ttl {
+			delete(cache.profiles, userID)
+		}
+	}
+}
+
+// Invalidate drops a cached profile so the next load fetches it again.
+func (cache *ProfileCache) Invalidate(userID string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	delete(cache.profiles, userID)
+}
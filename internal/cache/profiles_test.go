@@ -0,0 +1,26 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpiredProfilesAreRemoved(t *testing.T) {
+	cache := NewProfileCache(time.Minute)
+	fetch := func() (*Profile, error) { return &Profile{}, nil }
+
+	old, _ := cache.Load("old", fetch)
+	old.FetchedAt = time.Now().Add(-2 * time.Minute)
+
+	// Storing another profile sweeps the expired one
+	_, _ = cache.Load("new", fetch)
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if _, ok := cache.profiles["old"]; ok {
+		t.Fatal("expired profile still cached")
+	}
+	if _, ok := cache.profiles["new"]; !ok {
+		t.Fatal("new profile not cached")
+	}
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/sentinelb51/revoltgo"
+)
+
+// messageRange is a run of a channel's messages known to have no other messages between them.
+// Bounds are message IDs (ULIDs), which sort by time; a bound may belong to a since deleted message.
+type messageRange struct {
+	Oldest string `json:"oldest"`
+	Newest string `json:"newest"`
+}
+
+// contains reports whether a message ID falls within the range.
+func (r messageRange) contains(id string) bool {
+	return r.Oldest <= id && id <= r.Newest
+}
+
+// overlaps reports whether two ranges share at least one position.
+func (r messageRange) overlaps(other messageRange) bool {
+	return other.Oldest <= r.Newest && r.Oldest <= other.Newest
+}
+
+// spanOf returns the range covering messages sorted oldest to newest.
+func spanOf(messages []*revoltgo.Message) messageRange {
+	return messageRange{Oldest: messages[0].ID, Newest: messages[len(messages)-1].ID}
+}
+
+// addRange returns ranges with r added, merging every range it overlaps.
+// The input is not modified; ranges stay sorted oldest to newest.
+func addRange(ranges []messageRange, r messageRange) []messageRange {
+	merged := make([]messageRange, 0, len(ranges)+1)
+	for _, existing := range ranges {
+		if existing.overlaps(r) {
+			r.Oldest = min(r.Oldest, existing.Oldest)
+			r.Newest = max(r.Newest, existing.Newest)
+			continue
+		}
+		merged = append(merged, existing)
+	}
+
+	index, _ := slices.BinarySearchFunc(merged, r, func(a, b messageRange) int {
+		return strings.Compare(a.Oldest, b.Oldest)
+	})
+	return slices.Insert(merged, index, r)
+}
+
+// clipRanges returns ranges without the parts older than oldestID.
+func clipRanges(ranges []messageRange, oldestID string) []messageRange {
+	clipped := make([]messageRange, 0, len(ranges))
+	for _, r := range ranges {
+		if r.Newest < oldestID {
+			continue
+		}
+		r.Oldest = max(r.Oldest, oldestID)
+		clipped = append(clipped, r)
+	}
+	return clipped
+}
+
+// mergeMessages returns the union of two ID-sorted message slices; incoming messages replace cached ones.
+// Neither input is modified, since callers may still be iterating them.
+func mergeMessages(cached, incoming []*revoltgo.Message) []*revoltgo.Message {
+	merged := make([]*revoltgo.Message, 0, len(cached)+len(incoming))
+	i, j := 0, 0
+	for i < len(cached) && j < len(incoming) {
+		switch strings.Compare(cached[i].ID, incoming[j].ID) {
+		case -1:
+			merged = append(merged, cached[i])
+			i++
+		case 1:
+			merged = append(merged, incoming[j])
+			j++
+		default:
+			merged = append(merged, incoming[j])
+			i++
+			j++
+		}
+	}
+	merged = append(merged, cached[i:]...)
+	return append(merged, incoming[j:]...)
+}
+
+// indexFrom returns the index of the first message with an ID at or after id.
+func indexFrom(messages []*revoltgo.Message, id string) int {
+	index, _ := slices.BinarySearchFunc(messages, id, func(m *revoltgo.Message, id string) int {
+		return strings.Compare(m.ID, id)
+	})
+	return index
+}
@@ -0,0 +1,158 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/sentinelb51/revoltgo"
+)
+
+// referenceRetryDelay is how long a message that could not be fetched is not requested again.
+const referenceRetryDelay = time.Minute
+
+// ReferenceCache holds messages fetched individually, such as the targets of replies to old messages.
+// It is bounded: the least recently used entries are evicted first. Lookups in flight are tracked so
+// a message referenced by many replies is only fetched once.
+type ReferenceCache struct {
+	mutex      sync.Mutex
+	messages   map[string]*list.Element // messageID → element of order
+	order      *list.List               // *revoltgo.Message, most recently used first
+	inflight   map[string]bool          // messageID → fetch in progress
+	failed     map[string]time.Time     // messageID → when its fetch failed; retried after referenceRetryDelay
+	maxEntries int
+	now        func() time.Time
+}
+
+// NewReferenceCache creates a reference cache holding at most maxEntries messages.
+func NewReferenceCache(maxEntries int) *ReferenceCache {
+	return &ReferenceCache{
+		messages:   make(map[string]*list.Element),
+		order:      list.New(),
+		inflight:   make(map[string]bool),
+		failed:     make(map[string]time.Time),
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+// Get returns a cached message and marks it as just used, or nil.
+func (cache *ReferenceCache) Get(messageID string) *revoltgo.Message {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element := cache.messages[messageID]
+	if element == nil {
+		return nil
+	}
+	cache.order.MoveToFront(element)
+	return element.Value.(*revoltgo.Message)
+}
+
+// Set stores a message, evicting the least recently used entries beyond the limit.
+func (cache *ReferenceCache) Set(message *revoltgo.Message) {
+	if message == nil || message.ID == "" {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	delete(cache.inflight, message.ID)
+	delete(cache.failed, message.ID)
+
+	if element := cache.messages[message.ID]; element != nil {
+		element.Value = message
+		cache.order.MoveToFront(element)
+		return
+	}
+	cache.messages[message.ID] = cache.order.PushFront(message)
+
+	for cache.order.Len() > cache.maxEntries {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.messages, oldest.Value.(*revoltgo.Message).ID)
+	}
+}
+
+// Merge applies a message update to a cached message, like MessageCache.Merge.
+// Returns the updated message, or nil if the message is not cached.
+func (cache *ReferenceCache) Merge(messageID string, partial *revoltgo.Message) *revoltgo.Message {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element := cache.messages[messageID]
+	if element == nil {
+		return nil
+	}
+
+	// Widgets keep pointers to the old message, so it is never mutated in place
+	updated := *element.Value.(*revoltgo.Message)
+	mergeMessage(&updated, partial)
+	element.Value = &updated
+	return &updated
+}
+
+// Update replaces a cached message with a newer copy, e.g. one fetched again.
+// Messages that are not cached are ignored.
+func (cache *ReferenceCache) Update(message *revoltgo.Message) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element := cache.messages[message.ID]; element != nil {
+		element.Value = message
+	}
+}
+
+// Begin marks a message as being fetched.
+// Returns false if it is cached, already being fetched, or failed less than referenceRetryDelay ago.
+func (cache *ReferenceCache) Begin(messageID string) bool {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if cache.messages[messageID] != nil || cache.inflight[messageID] {
+		return false
+	}
+	if failedAt, ok := cache.failed[messageID]; ok {
+		if cache.now().Sub(failedAt) < referenceRetryDelay {
+			return false
+		}
+		delete(cache.failed, messageID)
+	}
+
+	cache.inflight[messageID] = true
+	return true
+}
+
+// Fail records that a message could not be fetched, so it is not requested again for a while.
+func (cache *ReferenceCache) Fail(messageID string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	delete(cache.inflight, messageID)
+
+	// Bound the failures the same way: expired ones go first, then all of them
+	now := cache.now()
+	if len(cache.failed) >= cache.maxEntries {
+		for id, failedAt := range cache.failed {
+			if now.Sub(failedAt) >= referenceRetryDelay {
+				delete(cache.failed, id)
+			}
+		}
+	}
+	if len(cache.failed) >= cache.maxEntries {
+		clear(cache.failed)
+	}
+	cache.failed[messageID] = now
+}
+
+// Remove forgets a message, e.g. after it was deleted.
+func (cache *ReferenceCache) Remove(messageID string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element := cache.messages[messageID]; element != nil {
+		cache.order.Remove(element)
+		delete(cache.messages, messageID)
+	}
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sentinelb51/revoltgo"
+)
+
+func TestReferencesEvictLeastRecentlyUsed(t *testing.T) {
+	cache := NewReferenceCache(2)
+	cache.Set(&revoltgo.Message{ID: "a"})
+	cache.Set(&revoltgo.Message{ID: "b"})
+
+	// a is used again, so b is the one to go
+	cache.Get("a")
+	cache.Set(&revoltgo.Message{ID: "c"})
+
+	for id, kept := range map[string]bool{"a": true, "b": false, "c": true} {
+		if cached := cache.Get(id) != nil; cached != kept {
+			t.Errorf("%s cached = %v, want %v", id, cached, kept)
+		}
+	}
+}
+
+func TestFailedReferencesAreRetried(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cache := NewReferenceCache(10)
+	cache.now = func() time.Time { return now }
+
+	if !cache.Begin("a") {
+		t.Fatal("Begin() = false for a new message")
+	}
+	cache.Fail("a")
+	if cache.Begin("a") {
+		t.Fatal("Begin() = true right after the fetch failed")
+	}
+
+	now = now.Add(referenceRetryDelay)
+	if !cache.Begin("a") {
+		t.Fatal("Begin() = false after the retry delay")
+	}
+}
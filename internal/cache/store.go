@@ -0,0 +1,293 @@
+package cache
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/sentinelb51/revoltgo"
+)
+
+// storeQueueSize is the number of pending store operations before writers block.
+const storeQueueSize = 256
+
+// storeMaxRecordSize bounds a single log line (a message with embeds and attachments).
+const storeMaxRecordSize = 1024 * 1024
+
+// MessageStore persists channel messages on disk, so history shows instantly on launch and offline.
+// Each channel has an append-only log of JSON lines (stored or deleted messages, and ranges known to be
+// complete) in the account's directory.
+// A single goroutine owns the files: writes never wait for the disk, and loads see every earlier write.
+type MessageStore struct {
+	accountID string
+	dir       string
+	retention int // Messages kept per channel when a log is compacted
+
+	mutex  sync.RWMutex
+	closed bool
+	ops    chan func()
+	done   chan struct{}
+
+	records map[string]int // channelID → lines in the log; only used by the store goroutine
+}
+
+// storeRecord is one line of a channel log.
+type storeRecord struct {
+	Message *revoltgo.Message `json:"message,omitempty"`
+	Deleted string            `json:"deleted,omitempty"` // ID of a deleted message
+	Range   *messageRange     `json:"range,omitempty"`   // Applied after Message
+}
+
+// OpenMessageStore opens the message store of an account, keeping up to retention messages per channel.
+func OpenMessageStore(accountID string, retention int) (*MessageStore, error) {
+	dir := getAppCacheDir("messages", accountID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create message store directory: %w", err)
+	}
+
+	store := &MessageStore{
+		accountID: accountID,
+		dir:       dir,
+		retention: retention,
+		ops:       make(chan func(), storeQueueSize),
+		done:      make(chan struct{}),
+		records:   make(map[string]int),
+	}
+	go store.run()
+	return store, nil
+}
+
+// AccountID returns the ID of the account the store belongs to.
+func (store *MessageStore) AccountID() string {
+	return store.accountID
+}
+
+// run executes queued operations in order until the store is closed.
+func (store *MessageStore) run() {
+	defer close(store.done)
+	for op := range store.ops {
+		op()
+	}
+}
+
+// enqueue queues an operation. Returns false if the store is closed.
+func (store *MessageStore) enqueue(op func()) bool {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	if store.closed {
+		return false
+	}
+	store.ops <- op
+	return true
+}
+
+// Put records new, fetched or edited messages of a channel, and the range they complete if span is not nil.
+func (store *MessageStore) Put(channelID string, span *messageRange, messages ...*revoltgo.Message) {
+	if len(messages) == 0 && span == nil {
+		return
+	}
+
+	records := make([]storeRecord, len(messages))
+	for i, message := range messages {
+		records[i] = storeRecord{Message: message}
+	}
+	if span != nil {
+		if len(records) == 0 {
+			records = append(records, storeRecord{})
+		}
+		records[len(records)-1].Range = span
+	}
+	store.enqueue(func() {
+		store.append(channelID, records)
+	})
+}
+
+// Delete records that a message was deleted.
+func (store *MessageStore) Delete(channelID, messageID string) {
+	store.enqueue(func() {
+		store.append(channelID, []storeRecord{{Deleted: messageID}})
+	})
+}
+
+// Load returns up to limit of the newest stored messages of a channel and the ranges among them,
+// both sorted oldest to newest. Blocks until earlier writes are on disk.
+func (store *MessageStore) Load(channelID string, limit int) ([]*revoltgo.Message, []messageRange) {
+	type loaded struct {
+		messages []*revoltgo.Message
+		ranges   []messageRange
+	}
+
+	result := make(chan loaded, 1)
+	if !store.enqueue(func() {
+		messages, ranges := store.load(channelID, limit)
+		result <- loaded{messages, ranges}
+	}) {
+		return nil, nil
+	}
+
+	r := <-result
+	return r.messages, r.ranges
+}
+
+// Close writes pending operations and stops the store. Later calls do nothing.
+func (store *MessageStore) Close() {
+	store.mutex.Lock()
+	if store.closed {
+		store.mutex.Unlock()
+		return
+	}
+	store.closed = true
+	close(store.ops)
+	store.mutex.Unlock()
+
+	<-store.done
+}
+
+// logPath returns the path of a channel's log.
+func (store *MessageStore) logPath(channelID string) string {
+	return filepath.Join(store.dir, channelID+".jsonl")
+}
+
+// append writes records to the end of a channel's log, compacting it once it grows past twice the retention.
+func (store *MessageStore) append(channelID string, records []storeRecord) {
+	file, err := os.OpenFile(store.logPath(channelID), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		fmt.Printf("Failed to open message log of %s: %v\n", channelID, err)
+		return
+	}
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			fmt.Printf("Failed to encode message record: %v\n", err)
+		}
+	}
+
+	err = writer.Flush()
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Printf("Failed to write message log of %s: %v\n", channelID, err)
+		return
+	}
+
+	// Logs not read yet this session are counted (and compacted if needed) by their first load
+	if count, known := store.records[channelID]; known {
+		store.records[channelID] = count + len(records)
+		if store.records[channelID] > 2*store.retention {
+			messages, ranges := store.replay(channelID)
+			store.compact(channelID, messages, ranges)
+		}
+	}
+}
+
+// load replays a channel's log and returns the newest messages and their ranges, compacting the log if needed.
+func (store *MessageStore) load(channelID string, limit int) ([]*revoltgo.Message, []messageRange) {
+	messages, ranges := store.replay(channelID)
+	if store.records[channelID] > 2*store.retention {
+		messages, ranges = store.compact(channelID, messages, ranges)
+	}
+
+	if len(messages) > limit {
+		messages = messages[len(messages)-limit:]
+		ranges = clipRanges(ranges, messages[0].ID)
+	}
+	return messages, ranges
+}
+
+// replay reads a channel's log into its current messages and ranges, sorted oldest to newest.
+// Lines that fail to decode (e.g. cut short by a crash) are skipped.
+func (store *MessageStore) replay(channelID string) ([]*revoltgo.Message, []messageRange) {
+	file, err := os.Open(store.logPath(channelID))
+	if err != nil {
+		store.records[channelID] = 0
+		return nil, nil
+	}
+	defer file.Close()
+
+	byID := make(map[string]*revoltgo.Message)
+	var ranges []messageRange
+	lines := 0
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), storeMaxRecordSize)
+	for scanner.Scan() {
+		lines++
+
+		var record storeRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+
+		switch {
+		case record.Message != nil && record.Message.ID != "":
+			byID[record.Message.ID] = record.Message
+		case record.Deleted != "":
+			delete(byID, record.Deleted)
+		}
+		if record.Range != nil {
+			ranges = addRange(ranges, *record.Range)
+		}
+	}
+	store.records[channelID] = lines
+
+	// IDs are ULIDs, so sorting them orders messages by time
+	messages := slices.SortedFunc(maps.Values(byID), func(a, b *revoltgo.Message) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return messages, ranges
+}
+
+// compact rewrites a channel's log with only its newest retained messages and their ranges, and returns them.
+// The log is replaced atomically, so a crash leaves either the old or the new log.
+func (store *MessageStore) compact(channelID string, messages []*revoltgo.Message, ranges []messageRange) ([]*revoltgo.Message, []messageRange) {
+	if len(messages) > store.retention {
+		messages = messages[len(messages)-store.retention:]
+		ranges = clipRanges(ranges, messages[0].ID)
+	}
+
+	path := store.logPath(channelID)
+	temp, err := os.CreateTemp(store.dir, channelID+".*.tmp")
+	if err != nil {
+		fmt.Printf("Failed to compact message log of %s: %v\n", channelID, err)
+		return messages, ranges
+	}
+
+	writer := bufio.NewWriter(temp)
+	encoder := json.NewEncoder(writer)
+	for _, message := range messages {
+		if err = encoder.Encode(storeRecord{Message: message}); err != nil {
+			break
+		}
+	}
+	for i := 0; i < len(ranges) && err == nil; i++ {
+		err = encoder.Encode(storeRecord{Range: &ranges[i]})
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), path)
+	}
+
+	if err != nil {
+		_ = os.Remove(temp.Name())
+		fmt.Printf("Failed to compact message log of %s: %v\n", channelID, err)
+		return messages, ranges
+	}
+
+	store.records[channelID] = len(messages) + len(ranges)
+	return messages, ranges
+}
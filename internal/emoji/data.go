@@ -0,0 +1,479 @@
+package emoji
+
+// categories lists Unicode emoji grouped by picker category, in display order.
+var categories = []Category{
+	{
+		Name: "Smileys & People",
+		Emoji: []Emoji{
+			{Value: "😀", Name: "grinning"},
+			{Value: "😃", Name: "smiley"},
+			{Value: "😄", Name: "smile"},
+			{Value: "😁", Name: "grin"},
+			{Value: "😆", Name: "laughing"},
+			{Value: "😅", Name: "sweat_smile"},
+			{Value: "🤣", Name: "rofl"},
+			{Value: "😂", Name: "joy"},
+			{Value: "🙂", Name: "slight_smile"},
+			{Value: "🙃", Name: "upside_down"},
+			{Value: "😉", Name: "wink"},
+			{Value: "😊", Name: "blush"},
+			{Value: "😇", Name: "innocent"},
+			{Value: "🥰", Name: "smiling_face_with_hearts"},
+			{Value: "😍", Name: "heart_eyes"},
+			{Value: "🤩", Name: "star_struck"},
+			{Value: "😘", Name: "kissing_heart"},
+			{Value: "😋", Name: "yum"},
+			{Value: "😛", Name: "stuck_out_tongue"},
+			{Value: "😜", Name: "stuck_out_tongue_winking_eye"},
+			{Value: "🤪", Name: "zany_face"},
+			{Value: "🤑", Name: "money_mouth"},
+			{Value: "🤗", Name: "hugging"},
+			{Value: "🤔", Name: "thinking"},
+			{Value: "🤐", Name: "zipper_mouth"},
+			{Value: "🤨", Name: "raised_eyebrow"},
+			{Value: "😐", Name: "neutral_face"},
+			{Value: "😑", Name: "expressionless"},
+			{Value: "😶", Name: "no_mouth"},
+			{Value: "😏", Name: "smirk"},
+			{Value: "😒", Name: "unamused"},
+			{Value: "🙄", Name: "rolling_eyes"},
+			{Value: "😬", Name: "grimacing"},
+			{Value: "😌", Name: "relieved"},
+			{Value: "😔", Name: "pensive"},
+			{Value: "😪", Name: "sleepy"},
+			{Value: "😴", Name: "sleeping"},
+			{Value: "😷", Name: "mask"},
+			{Value: "🤢", Name: "nauseated_face"},
+			{Value: "🤧", Name: "sneezing_face"},
+			{Value: "🥵", Name: "hot_face"},
+			{Value: "🥶", Name: "cold_face"},
+			{Value: "😵", Name: "dizzy_face"},
+			{Value: "🤯", Name: "exploding_head"},
+			{Value: "🤠", Name: "cowboy"},
+			{Value: "🥳", Name: "partying_face"},
+			{Value: "😎", Name: "sunglasses"},
+			{Value: "🤓", Name: "nerd"},
+			{Value: "😕", Name: "confused"},
+			{Value: "😟", Name: "worried"},
+			{Value: "🙁", Name: "slight_frown"},
+			{Value: "😮", Name: "open_mouth"},
+			{Value: "😲", Name: "astonished"},
+			{Value: "😳", Name: "flushed"},
+			{Value: "🥺", Name: "pleading_face"},
+			{Value: "😨", Name: "fearful"},
+			{Value: "😰", Name: "cold_sweat"},
+			{Value: "😢", Name: "cry"},
+			{Value: "😭", Name: "sob"},
+			{Value: "😱", Name: "scream"},
+			{Value: "😖", Name: "confounded"},
+			{Value: "😣", Name: "persevere"},
+			{Value: "😞", Name: "disappointed"},
+			{Value: "😓", Name: "sweat"},
+			{Value: "😩", Name: "weary"},
+			{Value: "😫", Name: "tired_face"},
+			{Value: "🥱", Name: "yawning_face"},
+			{Value: "😤", Name: "triumph"},
+			{Value: "😡", Name: "rage"},
+			{Value: "😠", Name: "angry"},
+			{Value: "💀", Name: "skull"},
+			{Value: "💩", Name: "poop"},
+			{Value: "🤡", Name: "clown"},
+			{Value: "👻", Name: "ghost"},
+			{Value: "👽", Name: "alien"},
+			{Value: "🤖", Name: "robot"},
+			{Value: "👋", Name: "wave"},
+			{Value: "👌", Name: "ok_hand"},
+			{Value: "🤌", Name: "pinched_fingers"},
+			{Value: "✌️", Name: "v"},
+			{Value: "🤞", Name: "crossed_fingers"},
+			{Value: "🤙", Name: "call_me"},
+			{Value: "👈", Name: "point_left"},
+			{Value: "👉", Name: "point_right"},
+			{Value: "👆", Name: "point_up"},
+			{Value: "👇", Name: "point_down"},
+			{Value: "👍", Name: "thumbsup"},
+			{Value: "👎", Name: "thumbsdown"},
+			{Value: "👊", Name: "fist"},
+			{Value: "👏", Name: "clap"},
+			{Value: "🙌", Name: "raised_hands"},
+			{Value: "👐", Name: "open_hands"},
+			{Value: "🤝", Name: "handshake"},
+			{Value: "🙏", Name: "pray"},
+			{Value: "💪", Name: "muscle"},
+			{Value: "👀", Name: "eyes"},
+			{Value: "🧠", Name: "brain"},
+			{Value: "🤷", Name: "person_shrugging"},
+			{Value: "🤦", Name: "person_facepalming"},
+			{Value: "🙆", Name: "ok_person"},
+			{Value: "🙅", Name: "no_good"},
+			{Value: "🙋", Name: "raising_hand"},
+			{Value: "🙇", Name: "bow"},
+		},
+	},
+	{
+		Name: "Nature",
+		Emoji: []Emoji{
+			{Value: "🐶", Name: "dog"},
+			{Value: "🐱", Name: "cat"},
+			{Value: "🐭", Name: "mouse"},
+			{Value: "🐹", Name: "hamster"},
+			{Value: "🐰", Name: "rabbit"},
+			{Value: "🦊", Name: "fox"},
+			{Value: "🐻", Name: "bear"},
+			{Value: "🐼", Name: "panda"},
+			{Value: "🐨", Name: "koala"},
+			{Value: "🐯", Name: "tiger"},
+			{Value: "🦁", Name: "lion"},
+			{Value: "🐮", Name: "cow"},
+			{Value: "🐷", Name: "pig"},
+			{Value: "🐸", Name: "frog"},
+			{Value: "🐵", Name: "monkey"},
+			{Value: "🙈", Name: "see_no_evil"},
+			{Value: "🐔", Name: "chicken"},
+			{Value: "🐧", Name: "penguin"},
+			{Value: "🐦", Name: "bird"},
+			{Value: "🦆", Name: "duck"},
+			{Value: "🦅", Name: "eagle"},
+			{Value: "🦉", Name: "owl"},
+			{Value: "🐺", Name: "wolf"},
+			{Value: "🐴", Name: "horse"},
+			{Value: "🦄", Name: "unicorn"},
+			{Value: "🐝", Name: "bee"},
+			{Value: "🐛", Name: "bug"},
+			{Value: "🦋", Name: "butterfly"},
+			{Value: "🐌", Name: "snail"},
+			{Value: "🐢", Name: "turtle"},
+			{Value: "🐍", Name: "snake"},
+			{Value: "🐙", Name: "octopus"},
+			{Value: "🦀", Name: "crab"},
+			{Value: "🐟", Name: "fish"},
+			{Value: "🐬", Name: "dolphin"},
+			{Value: "🐳", Name: "whale"},
+			{Value: "🦈", Name: "shark"},
+			{Value: "🐊", Name: "crocodile"},
+			{Value: "🦥", Name: "sloth"},
+			{Value: "🌵", Name: "cactus"},
+			{Value: "🌲", Name: "evergreen_tree"},
+			{Value: "🌳", Name: "deciduous_tree"},
+			{Value: "🌴", Name: "palm_tree"},
+			{Value: "🌱", Name: "seedling"},
+			{Value: "🌿", Name: "herb"},
+			{Value: "🍀", Name: "four_leaf_clover"},
+			{Value: "🍁", Name: "maple_leaf"},
+			{Value: "🍄", Name: "mushroom"},
+			{Value: "🌹", Name: "rose"},
+			{Value: "🌻", Name: "sunflower"},
+			{Value: "🌷", Name: "tulip"},
+			{Value: "🌸", Name: "cherry_blossom"},
+			{Value: "☀️", Name: "sun"},
+			{Value: "⛅", Name: "partly_sunny"},
+			{Value: "☁️", Name: "cloud"},
+			{Value: "🌧️", Name: "rain_cloud"},
+			{Value: "⚡", Name: "zap"},
+			{Value: "❄️", Name: "snowflake"},
+			{Value: "🔥", Name: "fire"},
+			{Value: "💧", Name: "droplet"},
+			{Value: "🌊", Name: "ocean"},
+			{Value: "🌈", Name: "rainbow"},
+			{Value: "⭐", Name: "star"},
+			{Value: "🌟", Name: "star2"},
+			{Value: "✨", Name: "sparkles"},
+			{Value: "🌙", Name: "crescent_moon"},
+			{Value: "🌎", Name: "earth_americas"},
+		},
+	},
+	{
+		Name: "Food & Drink",
+		Emoji: []Emoji{
+			{Value: "🍎", Name: "apple"},
+			{Value: "🍏", Name: "green_apple"},
+			{Value: "🍐", Name: "pear"},
+			{Value: "🍊", Name: "tangerine"},
+			{Value: "🍋", Name: "lemon"},
+			{Value: "🍌", Name: "banana"},
+			{Value: "🍉", Name: "watermelon"},
+			{Value: "🍇", Name: "grapes"},
+			{Value: "🍓", Name: "strawberry"},
+			{Value: "🍒", Name: "cherries"},
+			{Value: "🍑", Name: "peach"},
+			{Value: "🥭", Name: "mango"},
+			{Value: "🍍", Name: "pineapple"},
+			{Value: "🥥", Name: "coconut"},
+			{Value: "🥝", Name: "kiwi"},
+			{Value: "🍅", Name: "tomato"},
+			{Value: "🥑", Name: "avocado"},
+			{Value: "🍆", Name: "eggplant"},
+			{Value: "🥔", Name: "potato"},
+			{Value: "🥕", Name: "carrot"},
+			{Value: "🌽", Name: "corn"},
+			{Value: "🌶️", Name: "hot_pepper"},
+			{Value: "🥦", Name: "broccoli"},
+			{Value: "🍞", Name: "bread"},
+			{Value: "🥐", Name: "croissant"},
+			{Value: "🧀", Name: "cheese"},
+			{Value: "🥚", Name: "egg"},
+			{Value: "🥓", Name: "bacon"},
+			{Value: "🥞", Name: "pancakes"},
+			{Value: "🍔", Name: "hamburger"},
+			{Value: "🍟", Name: "fries"},
+			{Value: "🍕", Name: "pizza"},
+			{Value: "🌭", Name: "hotdog"},
+			{Value: "🥪", Name: "sandwich"},
+			{Value: "🌮", Name: "taco"},
+			{Value: "🌯", Name: "burrito"},
+			{Value: "🍝", Name: "spaghetti"},
+			{Value: "🍜", Name: "ramen"},
+			{Value: "🍣", Name: "sushi"},
+			{Value: "🍚", Name: "rice"},
+			{Value: "🍛", Name: "curry"},
+			{Value: "🥟", Name: "dumpling"},
+			{Value: "🍦", Name: "icecream"},
+			{Value: "🍩", Name: "doughnut"},
+			{Value: "🍪", Name: "cookie"},
+			{Value: "🍰", Name: "cake"},
+			{Value: "🎂", Name: "birthday"},
+			{Value: "🍫", Name: "chocolate_bar"},
+			{Value: "🍬", Name: "candy"},
+			{Value: "🍿", Name: "popcorn"},
+			{Value: "☕", Name: "coffee"},
+			{Value: "🍵", Name: "tea"},
+			{Value: "🥛", Name: "milk"},
+			{Value: "🍺", Name: "beer"},
+			{Value: "🍻", Name: "beers"},
+			{Value: "🍷", Name: "wine_glass"},
+			{Value: "🍸", Name: "cocktail"},
+			{Value: "🍹", Name: "tropical_drink"},
+			{Value: "🍾", Name: "champagne"},
+			{Value: "🥤", Name: "cup_with_straw"},
+		},
+	},
+	{
+		Name: "Activities",
+		Emoji: []Emoji{
+			{Value: "⚽", Name: "soccer"},
+			{Value: "🏀", Name: "basketball"},
+			{Value: "🏈", Name: "football"},
+			{Value: "⚾", Name: "baseball"},
+			{Value: "🎾", Name: "tennis"},
+			{Value: "🏐", Name: "volleyball"},
+			{Value: "🏉", Name: "rugby_football"},
+			{Value: "🎱", Name: "8ball"},
+			{Value: "🏓", Name: "ping_pong"},
+			{Value: "🏸", Name: "badminton"},
+			{Value: "🏒", Name: "hockey"},
+			{Value: "⛳", Name: "golf"},
+			{Value: "🏹", Name: "bow_and_arrow"},
+			{Value: "🎣", Name: "fishing_pole"},
+			{Value: "🥊", Name: "boxing_glove"},
+			{Value: "🥋", Name: "martial_arts_uniform"},
+			{Value: "🛹", Name: "skateboard"},
+			{Value: "🎿", Name: "ski"},
+			{Value: "⛸️", Name: "ice_skate"},
+			{Value: "🏆", Name: "trophy"},
+			{Value: "🏅", Name: "medal"},
+			{Value: "🥇", Name: "first_place"},
+			{Value: "🥈", Name: "second_place"},
+			{Value: "🥉", Name: "third_place"},
+			{Value: "🎮", Name: "video_game"},
+			{Value: "🕹️", Name: "joystick"},
+			{Value: "🎲", Name: "game_die"},
+			{Value: "🎯", Name: "dart"},
+			{Value: "🎳", Name: "bowling"},
+			{Value: "🧩", Name: "jigsaw"},
+			{Value: "♟️", Name: "chess_pawn"},
+			{Value: "🎨", Name: "art"},
+			{Value: "🎭", Name: "performing_arts"},
+			{Value: "🎤", Name: "microphone"},
+			{Value: "🎧", Name: "headphones"},
+			{Value: "🎵", Name: "musical_note"},
+			{Value: "🎶", Name: "notes"},
+			{Value: "🎸", Name: "guitar"},
+			{Value: "🎹", Name: "piano"},
+			{Value: "🎺", Name: "trumpet"},
+			{Value: "🎻", Name: "violin"},
+			{Value: "🥁", Name: "drum"},
+			{Value: "🎉", Name: "tada"},
+			{Value: "🎊", Name: "confetti_ball"},
+			{Value: "🎈", Name: "balloon"},
+			{Value: "🎁", Name: "gift"},
+			{Value: "🎀", Name: "ribbon"},
+			{Value: "🎄", Name: "christmas_tree"},
+			{Value: "🎃", Name: "jack_o_lantern"},
+		},
+	},
+	{
+		Name: "Travel & Places",
+		Emoji: []Emoji{
+			{Value: "🚗", Name: "car"},
+			{Value: "🚕", Name: "taxi"},
+			{Value: "🚌", Name: "bus"},
+			{Value: "🚓", Name: "police_car"},
+			{Value: "🚑", Name: "ambulance"},
+			{Value: "🚒", Name: "fire_engine"},
+			{Value: "🚚", Name: "truck"},
+			{Value: "🚜", Name: "tractor"},
+			{Value: "🚲", Name: "bike"},
+			{Value: "🏍️", Name: "motorcycle"},
+			{Value: "🚆", Name: "train"},
+			{Value: "🚇", Name: "metro"},
+			{Value: "✈️", Name: "airplane"},
+			{Value: "🚀", Name: "rocket"},
+			{Value: "🚁", Name: "helicopter"},
+			{Value: "⛵", Name: "sailboat"},
+			{Value: "🚢", Name: "ship"},
+			{Value: "⚓", Name: "anchor"},
+			{Value: "🚧", Name: "construction"},
+			{Value: "⛽", Name: "fuelpump"},
+			{Value: "🚨", Name: "rotating_light"},
+			{Value: "🏠", Name: "house"},
+			{Value: "🏢", Name: "office"},
+			{Value: "🏥", Name: "hospital"},
+			{Value: "🏫", Name: "school"},
+			{Value: "🏰", Name: "castle"},
+			{Value: "🏟️", Name: "stadium"},
+			{Value: "⛺", Name: "tent"},
+			{Value: "⛰️", Name: "mountain"},
+			{Value: "🌋", Name: "volcano"},
+			{Value: "🏖️", Name: "beach"},
+			{Value: "🏜️", Name: "desert"},
+			{Value: "🏝️", Name: "island"},
+			{Value: "🗽", Name: "statue_of_liberty"},
+			{Value: "🗺️", Name: "world_map"},
+			{Value: "🌌", Name: "milky_way"},
+			{Value: "🌃", Name: "night_with_stars"},
+			{Value: "🌅", Name: "sunrise"},
+			{Value: "🌆", Name: "city_sunset"},
+			{Value: "🌉", Name: "bridge_at_night"},
+		},
+	},
+	{
+		Name: "Objects",
+		Emoji: []Emoji{
+			{Value: "⌚", Name: "watch"},
+			{Value: "📱", Name: "iphone"},
+			{Value: "💻", Name: "computer"},
+			{Value: "⌨️", Name: "keyboard"},
+			{Value: "🖥️", Name: "desktop"},
+			{Value: "🖨️", Name: "printer"},
+			{Value: "🖱️", Name: "mouse_three_button"},
+			{Value: "💿", Name: "cd"},
+			{Value: "💾", Name: "floppy_disk"},
+			{Value: "📷", Name: "camera"},
+			{Value: "📹", Name: "video_camera"},
+			{Value: "📺", Name: "tv"},
+			{Value: "📻", Name: "radio"},
+			{Value: "☎️", Name: "telephone"},
+			{Value: "🔋", Name: "battery"},
+			{Value: "🔌", Name: "electric_plug"},
+			{Value: "💡", Name: "bulb"},
+			{Value: "🔦", Name: "flashlight"},
+			{Value: "🕯️", Name: "candle"},
+			{Value: "💸", Name: "money_with_wings"},
+			{Value: "💵", Name: "dollar"},
+			{Value: "💰", Name: "moneybag"},
+			{Value: "💳", Name: "credit_card"},
+			{Value: "💎", Name: "gem"},
+			{Value: "🔧", Name: "wrench"},
+			{Value: "🔨", Name: "hammer"},
+			{Value: "🛠️", Name: "tools"},
+			{Value: "⚙️", Name: "gear"},
+			{Value: "🔩", Name: "nut_and_bolt"},
+			{Value: "🔗", Name: "link"},
+			{Value: "🔒", Name: "lock"},
+			{Value: "🔓", Name: "unlock"},
+			{Value: "🔑", Name: "key"},
+			{Value: "🛡️", Name: "shield"},
+			{Value: "💣", Name: "bomb"},
+			{Value: "💊", Name: "pill"},
+			{Value: "💉", Name: "syringe"},
+			{Value: "🧬", Name: "dna"},
+			{Value: "🔬", Name: "microscope"},
+			{Value: "🔭", Name: "telescope"},
+			{Value: "📚", Name: "books"},
+			{Value: "📖", Name: "book"},
+			{Value: "✏️", Name: "pencil2"},
+			{Value: "🖊️", Name: "pen"},
+			{Value: "📝", Name: "memo"},
+			{Value: "📎", Name: "paperclip"},
+			{Value: "📌", Name: "pushpin"},
+			{Value: "✂️", Name: "scissors"},
+			{Value: "📅", Name: "calendar"},
+			{Value: "📈", Name: "chart_with_upwards_trend"},
+			{Value: "📋", Name: "clipboard"},
+			{Value: "📁", Name: "file_folder"},
+			{Value: "🗑️", Name: "wastebasket"},
+			{Value: "✉️", Name: "envelope"},
+			{Value: "📫", Name: "mailbox"},
+			{Value: "📦", Name: "package"},
+			{Value: "🔔", Name: "bell"},
+			{Value: "📢", Name: "loudspeaker"},
+			{Value: "⌛", Name: "hourglass"},
+			{Value: "⏰", Name: "alarm_clock"},
+			{Value: "🧲", Name: "magnet"},
+			{Value: "👑", Name: "crown"},
+			{Value: "👓", Name: "eyeglasses"},
+		},
+	},
+	{
+		Name: "Symbols",
+		Emoji: []Emoji{
+			{Value: "❤️", Name: "heart"},
+			{Value: "🧡", Name: "orange_heart"},
+			{Value: "💛", Name: "yellow_heart"},
+			{Value: "💚", Name: "green_heart"},
+			{Value: "💙", Name: "blue_heart"},
+			{Value: "💜", Name: "purple_heart"},
+			{Value: "🖤", Name: "black_heart"},
+			{Value: "🤍", Name: "white_heart"},
+			{Value: "💔", Name: "broken_heart"},
+			{Value: "💕", Name: "two_hearts"},
+			{Value: "💖", Name: "sparkling_heart"},
+			{Value: "💗", Name: "heartpulse"},
+			{Value: "💯", Name: "100"},
+			{Value: "💢", Name: "anger"},
+			{Value: "💥", Name: "boom"},
+			{Value: "💫", Name: "dizzy"},
+			{Value: "💬", Name: "speech_balloon"},
+			{Value: "💭", Name: "thought_balloon"},
+			{Value: "💤", Name: "zzz"},
+			{Value: "✅", Name: "white_check_mark"},
+			{Value: "✔️", Name: "heavy_check_mark"},
+			{Value: "❌", Name: "x"},
+			{Value: "❎", Name: "negative_squared_cross_mark"},
+			{Value: "➕", Name: "heavy_plus_sign"},
+			{Value: "➖", Name: "heavy_minus_sign"},
+			{Value: "❓", Name: "question"},
+			{Value: "❗", Name: "exclamation"},
+			{Value: "‼️", Name: "bangbang"},
+			{Value: "⁉️", Name: "interrobang"},
+			{Value: "⚠️", Name: "warning"},
+			{Value: "⛔", Name: "no_entry"},
+			{Value: "🚫", Name: "no_entry_sign"},
+			{Value: "♻️", Name: "recycle"},
+			{Value: "♾️", Name: "infinity"},
+			{Value: "⬆️", Name: "arrow_up"},
+			{Value: "⬇️", Name: "arrow_down"},
+			{Value: "⬅️", Name: "arrow_left"},
+			{Value: "➡️", Name: "arrow_right"},
+			{Value: "🔄", Name: "arrows_counterclockwise"},
+			{Value: "🆕", Name: "new"},
+			{Value: "🆓", Name: "free"},
+			{Value: "🆒", Name: "cool"},
+			{Value: "🆗", Name: "ok"},
+			{Value: "🆘", Name: "sos"},
+			{Value: "🆙", Name: "up"},
+			{Value: "🔴", Name: "red_circle"},
+			{Value: "🟠", Name: "orange_circle"},
+			{Value: "🟡", Name: "yellow_circle"},
+			{Value: "🟢", Name: "green_circle"},
+			{Value: "🔵", Name: "blue_circle"},
+			{Value: "🟣", Name: "purple_circle"},
+			{Value: "⚫", Name: "black_circle"},
+			{Value: "⚪", Name: "white_circle"},
+			{Value: "🔱", Name: "trident"},
+			{Value: "©️", Name: "copyright"},
+			{Value: "®️", Name: "registered"},
+			{Value: "™️", Name: "tm"},
+		},
+	},
+}
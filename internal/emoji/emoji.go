@@ -0,0 +1,66 @@
+// Package emoji provides the Unicode emoji set, shortcode search and recently used tracking.
+package emoji
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Emoji is a Unicode emoji or a server custom emoji.
+// Value is what gets sent: the Unicode character, or the custom emoji ID.
+type Emoji struct {
+	Value  string
+	Name   string // Shortcode without colons
+	Custom bool
+}
+
+// Text returns the form inserted into message content.
+// Custom emoji use Revolt's :ID: syntax.
+func (e Emoji) Text() string {
+	if e.Custom {
+		return fmt.Sprintf(":%s:", e.Value)
+	}
+	return e.Value
+}
+
+// Category is a named group of emoji shown together in the picker.
+type Category struct {
+	Name  string
+	Emoji []Emoji
+}
+
+// Categories returns the Unicode emoji grouped by category.
+func Categories() []Category {
+	return categories
+}
+
+// Search returns emoji whose shortcode contains query, prefix matches first.
+func Search(query string, pool []Emoji, limit int) []Emoji {
+	query = strings.ToLower(query)
+
+	var prefix, contains []Emoji
+	for _, e := range pool {
+		name := strings.ToLower(e.Name)
+		switch {
+		case strings.HasPrefix(name, query):
+			prefix = append(prefix, e)
+		case strings.Contains(name, query):
+			contains = append(contains, e)
+		}
+	}
+
+	results := append(prefix, contains...)
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// All returns every Unicode emoji, in category order.
+func All() []Emoji {
+	var all []Emoji
+	for _, category := range categories {
+		all = append(all, category.Emoji...)
+	}
+	return all
+}
@@ -0,0 +1,102 @@
+package emoji
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+const (
+	recentFileName = ".rgoclient_recent_emoji.json"
+	maxRecent      = 24
+)
+
+var (
+	recentMutex  sync.Mutex
+	recentValues []Emoji
+	recentLoaded bool
+
+	saveMutex sync.Mutex // Serializes writes, so the last one saves the latest list
+)
+
+// getRecentPath returns the path to the recent emoji file in the user's home directory.
+func getRecentPath() (string, error) {
+	homeDirectory, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDirectory, recentFileName), nil
+}
+
+// Recent returns recently used emoji, most recent first.
+func Recent() []Emoji {
+	recentMutex.Lock()
+	defer recentMutex.Unlock()
+
+	loadRecent()
+	return slices.Clone(recentValues)
+}
+
+// RecordUse moves an emoji to the front of the recent list.
+// The list is saved to disk in the background, so it is safe to call from the UI thread.
+func RecordUse(e Emoji) {
+	recentMutex.Lock()
+	loadRecent()
+
+	recentValues = slices.DeleteFunc(recentValues, func(existing Emoji) bool {
+		return existing.Value == e.Value
+	})
+	recentValues = slices.Insert(recentValues, 0, e)
+	if len(recentValues) > maxRecent {
+		recentValues = recentValues[:maxRecent]
+	}
+	recentMutex.Unlock()
+
+	go func() {
+		if err := saveRecent(); err != nil {
+			log.Printf("Failed to save recent emoji: %v\n", err)
+		}
+	}()
+}
+
+// saveRecent writes the current recent list to disk.
+func saveRecent() error {
+	saveMutex.Lock()
+	defer saveMutex.Unlock()
+
+	path, err := getRecentPath()
+	if err != nil {
+		return err
+	}
+
+	recentMutex.Lock()
+	data, err := json.Marshal(recentValues)
+	recentMutex.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// loadRecent reads the recent list from disk once. Call with recentMutex held.
+func loadRecent() {
+	if recentLoaded {
+		return
+	}
+	recentLoaded = true
+
+	path, err := getRecentPath()
+	if err != nil {
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	_ = json.Unmarshal(data, &recentValues)
+}
@@ -2,6 +2,9 @@ package interfaces
 
 import (
 	"github.com/sentinelb51/revoltgo"
+
+	"RGOClient/internal/cache"
+	"RGOClient/internal/emoji"
 )
 
 // MessageActions defines user interactions with messages.
@@ -9,11 +12,21 @@ import (
 type MessageActions interface {
 	// User interactions
 	OnAvatarTapped(userID string)
+	OnChannelTapped(channelID string) // Channel links in message content
 	OnImageTapped(attachment *revoltgo.Attachment)
 	OnReply(message *revoltgo.Message)
+	OnReplyTapped(channelID, messageID string) // Jumps to the replied-to message
 	OnDelete(messageID string)
 	OnEdit(messageID string)
+	OnReact(message *revoltgo.Message, emojiID string) // Toggles our reaction
 
-	// Message resolution (cache lookup, not network)
+	// Message resolution: CachedMessage only looks in the caches; ResolveMessage, for reply previews,
+	// also fetches an uncached message in the background and returns nil meanwhile
+	CachedMessage(channelID, messageID string) *revoltgo.Message
 	ResolveMessage(channelID, messageID string) *revoltgo.Message
+	IsMessageDeleted(messageID string) bool
+	CustomEmoji() []emoji.Category // Server emoji offered by the picker
+
+	// Image loading
+	ImageScope() *cache.ImageScope // Loads of the shown messages, owned by message ID
 }
@@ -0,0 +1,250 @@
+package markdown
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"fyne.io/fyne/v2/widget"
+
+	"RGOClient/internal/util"
+)
+
+// Fallback names for references that cannot be resolved.
+const (
+	unknownUser    = "Unknown user"
+	unknownChannel = "unknown-channel"
+)
+
+// inlineParser turns a single line into inline segments.
+type inlineParser struct {
+	renderer *Renderer
+	style    widget.RichTextStyle
+	segments []widget.RichTextSegment
+	text     strings.Builder
+}
+
+// parseInline parses text with the given base style.
+func (r *Renderer) parseInline(text string, style widget.RichTextStyle) []widget.RichTextSegment {
+	p := &inlineParser{renderer: r, style: style}
+	for i := 0; i < len(text); {
+		if n := p.token(text, i); n > 0 {
+			i += n
+			continue
+		}
+		p.text.WriteByte(text[i])
+		i++
+	}
+	p.flush()
+	return p.segments
+}
+
+// flush emits buffered plain text as a segment in the current style.
+func (p *inlineParser) flush() {
+	if p.text.Len() == 0 {
+		return
+	}
+	p.segments = append(p.segments, &widget.TextSegment{Style: p.style, Text: p.text.String()})
+	p.text.Reset()
+}
+
+// emit flushes pending text and appends segments.
+func (p *inlineParser) emit(segments ...widget.RichTextSegment) {
+	p.flush()
+	p.segments = append(p.segments, segments...)
+}
+
+// token tries to parse a construct at text[i:]. Returns the number of bytes consumed, or 0.
+func (p *inlineParser) token(text string, i int) int {
+	s := text[i:]
+
+	switch {
+	case s[0] == '\\' && len(s) > 1 && isPunct(s[1]):
+		p.text.WriteByte(s[1])
+		return 2
+
+	case s[0] == '`':
+		if end := strings.IndexByte(s[1:], '`'); end > 0 {
+			p.emit(&widget.TextSegment{Style: widget.RichTextStyleCodeInline, Text: s[1 : end+1]})
+			return end + 2
+		}
+
+	case strings.HasPrefix(s, "||"):
+		if end := strings.Index(s[2:], "||"); end > 0 {
+			p.emit(&SpoilerSegment{Text: s[2 : end+2]})
+			return end + 4
+		}
+
+	case strings.HasPrefix(s, "**"):
+		if end := strings.Index(s[2:], "**"); end > 0 {
+			style := p.style
+			style.TextStyle.Bold = true
+			p.emit(p.renderer.parseInline(s[2:end+2], style)...)
+			return end + 4
+		}
+
+	case s[0] == '*' || s[0] == '_':
+		return p.emphasis(text, i)
+
+	case strings.HasPrefix(s, "<@"), strings.HasPrefix(s, "<#"):
+		return p.reference(s)
+
+	case strings.HasPrefix(s, "<t:"):
+		return p.timestamp(s)
+
+	case s[0] == ':':
+		return p.emoji(s)
+
+	case s[0] == '[':
+		return p.link(s)
+
+	case strings.HasPrefix(s, "https://"), strings.HasPrefix(s, "http://"):
+		return p.autolink(s)
+	}
+
+	return 0
+}
+
+// emphasis parses *italic* and _italic_. Underscores only count at word boundaries,
+// so snake_case identifiers are left alone.
+func (p *inlineParser) emphasis(text string, i int) int {
+	delim := text[i]
+	s := text[i:]
+
+	if len(s) < 3 || s[1] == ' ' || s[1] == delim {
+		return 0
+	}
+	if delim == '_' && i > 0 && isWordByte(text[i-1]) {
+		return 0
+	}
+
+	end := strings.IndexByte(s[1:], delim) + 1
+	if end <= 1 || s[end-1] == ' ' {
+		return 0
+	}
+	if delim == '_' && end+1 < len(s) && isWordByte(s[end+1]) {
+		return 0
+	}
+
+	style := p.style
+	style.TextStyle.Italic = true
+	p.emit(p.renderer.parseInline(s[1:end], style)...)
+	return end + 1
+}
+
+// reference parses <@userID> mentions and <#channelID> channel links.
+func (p *inlineParser) reference(s string) int {
+	end := strings.IndexByte(s, '>')
+	if end < 0 || !util.IsULID(s[2:end]) {
+		return 0
+	}
+
+	id := s[2:end]
+	r := p.renderer
+
+	if s[1] == '@' {
+		name := r.resolve(Resolver.UserName, id, unknownUser)
+		p.emit(&widget.HyperlinkSegment{Text: fmt.Sprintf("@%s", name), OnTapped: bindTap(r.OnUserTapped, id)})
+	} else {
+		name := r.resolve(Resolver.ChannelName, id, unknownChannel)
+		p.emit(&widget.HyperlinkSegment{Text: fmt.Sprintf("#%s", name), OnTapped: bindTap(r.OnChannelTapped, id)})
+	}
+	return end + 1
+}
+
+// emoji parses :emojiID: custom emoji.
+func (p *inlineParser) emoji(s string) int {
+	end := strings.IndexByte(s[1:], ':') + 1
+	if end <= 1 || !util.IsCustomEmoji(s[1:end]) {
+		return 0
+	}
+
+	id := s[1:end]
+	p.emit(&EmojiSegment{ID: id, Name: p.renderer.resolve(Resolver.EmojiName, id, id)})
+	return end + 1
+}
+
+// link parses [text](https://url) links. The text ends at the bracket closing the opening one,
+// so "[a] [b](url)" links only "b".
+func (p *inlineParser) link(s string) int {
+	closeText := closingBracket(s)
+	if closeText < 1 || !strings.HasPrefix(s[closeText:], "](") {
+		return 0
+	}
+
+	closeURL := strings.IndexByte(s[closeText:], ')')
+	if closeURL < 0 {
+		return 0
+	}
+
+	target, err := url.Parse(s[closeText+2 : closeText+closeURL])
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") {
+		return 0
+	}
+
+	p.emit(&widget.HyperlinkSegment{Text: s[1:closeText], URL: target})
+	return closeText + closeURL + 1
+}
+
+// closingBracket returns the index of the ']' matching the '[' at s[0], or -1.
+// Escaped brackets do not count.
+func closingBracket(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// autolink parses bare http(s) URLs, leaving trailing punctuation as text.
+func (p *inlineParser) autolink(s string) int {
+	end := strings.IndexAny(s, " \t<>")
+	if end < 0 {
+		end = len(s)
+	}
+	end = len(strings.TrimRight(s[:end], ".,:;!?)'\""))
+
+	target, err := url.Parse(s[:end])
+	if err != nil || target.Host == "" {
+		return 0
+	}
+
+	p.emit(&widget.HyperlinkSegment{Text: s[:end], URL: target})
+	return end
+}
+
+// resolve looks up a name, returning fallback if the resolver is missing or does not know the ID.
+func (r *Renderer) resolve(lookup func(Resolver, string) string, id, fallback string) string {
+	if r.Resolver == nil {
+		return fallback
+	}
+	if name := lookup(r.Resolver, id); name != "" {
+		return name
+	}
+	return fallback
+}
+
+// bindTap wraps a callback with its argument, or returns nil.
+func bindTap(fn func(string), id string) func() {
+	if fn == nil {
+		return nil
+	}
+	return func() { fn(id) }
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isPunct(c byte) bool {
+	return strings.IndexByte("\\`*_{}[]()<>#+-.!|:~", c) >= 0
+}
@@ -0,0 +1,164 @@
+// Package markdown renders Revolt's markdown dialect into RichText segments.
+//
+// On top of the usual emphasis, code and quotes, Revolt messages contain
+// user mentions (<@id>), channel links (<#id>), custom emoji (:id:),
+// spoilers (||text||) and timestamps (<t:unix:format>).
+package markdown
+
+import (
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Quote styles. Fyne's blockquote style is a block of plain text, so quotes are inline text behind
+// a bar instead, keeping their mentions and links tappable.
+var (
+	styleQuote = widget.RichTextStyle{
+		ColorName: theme.ColorNameForeground,
+		Inline:    true,
+		SizeName:  theme.SizeNameText,
+		TextStyle: fyne.TextStyle{Italic: true},
+	}
+	styleQuoteBar = widget.RichTextStyle{
+		ColorName: theme.ColorNameDisabled,
+		Inline:    true,
+		SizeName:  theme.SizeNameText,
+	}
+)
+
+// Renderer converts message content into RichText segments.
+type Renderer struct {
+	Resolver Resolver
+
+	// Called when a mention or channel link is tapped
+	OnUserTapped    func(userID string)
+	OnChannelTapped func(channelID string)
+
+	// Time source and zone for timestamps; default to time.Now and time.Local
+	Now      func() time.Time
+	Location *time.Location
+}
+
+// NewRenderer creates a renderer that resolves names through the global session.
+func NewRenderer(onUserTapped, onChannelTapped func(string)) *Renderer {
+	return &Renderer{
+		Resolver:        SessionResolver{},
+		OnUserTapped:    onUserTapped,
+		OnChannelTapped: onChannelTapped,
+	}
+}
+
+// Render parses content line by line. Every rendered line ends with a paragraph break,
+// so the last segment is never inline.
+func (r *Renderer) Render(content string) []widget.RichTextSegment {
+	var segments []widget.RichTextSegment
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	pendingBlank := false
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" {
+			pendingBlank = len(segments) > 0
+			continue
+		}
+
+		// Preserve a single blank line between blocks
+		if pendingBlank {
+			segments = append(segments, paragraphBreak())
+			pendingBlank = false
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, "```") && hasClosingFence(lines[i+1:]):
+			var code []string
+			for i++; !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			if len(code) > 0 {
+				segments = append(segments, &widget.TextSegment{
+					Style: widget.RichTextStyleCodeBlock,
+					Text:  strings.Join(code, "\n"),
+				})
+			}
+
+		case strings.HasPrefix(trimmed, ">"):
+			quote := strings.TrimPrefix(strings.TrimPrefix(trimmed, ">"), " ")
+			segments = append(segments, &widget.TextSegment{Style: styleQuoteBar, Text: "▎ "})
+			segments = append(segments, r.parseInline(quote, styleQuote)...)
+			segments = append(segments, paragraphBreak())
+
+		case headingLevel(trimmed) > 0:
+			level := headingLevel(trimmed)
+			segments = append(segments, heading(level, r.plainText(trimmed[level+1:])))
+
+		default:
+			segments = append(segments, r.parseInline(line, widget.RichTextStyleInline)...)
+			segments = append(segments, paragraphBreak())
+		}
+	}
+
+	return segments
+}
+
+// plainText renders inline markup to a single string, for blocks that cannot hold mixed segments.
+// Spoilers are masked so they stay hidden.
+func (r *Renderer) plainText(text string) string {
+	var b strings.Builder
+	for _, segment := range r.parseInline(text, widget.RichTextStyleInline) {
+		if spoiler, ok := segment.(*SpoilerSegment); ok {
+			b.WriteString(strings.Repeat("█", len([]rune(spoiler.Text))))
+			continue
+		}
+		b.WriteString(segment.Textual())
+	}
+	return b.String()
+}
+
+// hasClosingFence reports whether a code block opened before lines is closed by one of them.
+// An unclosed fence is shown as text rather than swallowing the rest of the message.
+func hasClosingFence(lines []string) bool {
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			return true
+		}
+	}
+	return false
+}
+
+// headingLevel returns the number of leading '#' characters followed by a space, or 0.
+func headingLevel(line string) int {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+
+	if level == 0 || level > 6 || level >= len(line) || line[level] != ' ' {
+		return 0
+	}
+	return level
+}
+
+// heading mirrors fyne's markdown: two heading sizes, then bold paragraphs.
+func heading(level int, text string) widget.RichTextSegment {
+	switch level {
+	case 1:
+		return &widget.TextSegment{Style: widget.RichTextStyleHeading, Text: text}
+	case 2:
+		return &widget.TextSegment{Style: widget.RichTextStyleSubHeading, Text: text}
+	default:
+		segment := &widget.TextSegment{Style: widget.RichTextStyleParagraph, Text: text}
+		segment.Style.TextStyle.Bold = true
+		return segment
+	}
+}
+
+// paragraphBreak ends the current line.
+func paragraphBreak() widget.RichTextSegment {
+	return &widget.TextSegment{Style: widget.RichTextStyleParagraph}
+}
@@ -0,0 +1,140 @@
+package markdown
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// fakeResolver resolves names from fixed maps.
+type fakeResolver struct {
+	users, channels, emoji map[string]string
+}
+
+func (f fakeResolver) UserName(id string) string    { return f.users[id] }
+func (f fakeResolver) ChannelName(id string) string { return f.channels[id] }
+func (f fakeResolver) EmojiName(id string) string   { return f.emoji[id] }
+
+func newTestRenderer() *Renderer {
+	return &Renderer{
+		Resolver: fakeResolver{
+			users:    map[string]string{"01HZZZZZZZZZZZZZZZZZZZZZZA": "alice"},
+			channels: map[string]string{"01HZZZZZZZZZZZZZZZZZZZZZZC": "general"},
+			emoji:    map[string]string{"01HZZZZZZZZZZZZZZZZZZZZZZE": "party"},
+		},
+		OnUserTapped:    func(string) {},
+		OnChannelTapped: func(string) {},
+		Now:             func() time.Time { return time.Unix(1700000000, 0) },
+		Location:        time.UTC,
+	}
+}
+
+// TestGolden renders every testdata/*.md file and compares against its .golden dump.
+// Run with -update to regenerate the golden files.
+func TestGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("no golden inputs found")
+	}
+
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".md")
+		t.Run(name, func(t *testing.T) {
+			content, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := dump(newTestRenderer().Render(strings.TrimSuffix(string(content), "\n")))
+			goldenPath := strings.TrimSuffix(input, ".md") + ".golden"
+
+			if *update {
+				if err := os.WriteFile(goldenPath, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("missing golden file (run with -update): %v", err)
+			}
+			if got != string(want) {
+				t.Errorf("output mismatch for %s\n--- got ---\n%s--- want ---\n%s", input, got, want)
+			}
+		})
+	}
+}
+
+// dump writes one line per segment describing its type, style and text.
+func dump(segments []widget.RichTextSegment) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		switch s := segment.(type) {
+		case *widget.TextSegment:
+			fmt.Fprintf(&b, "text %s %q\n", styleName(s.Style), s.Text)
+		case *widget.HyperlinkSegment:
+			target := "tap"
+			if s.URL != nil {
+				target = s.URL.String()
+			} else if s.OnTapped == nil {
+				target = "none"
+			}
+			fmt.Fprintf(&b, "link %q -> %s\n", s.Text, target)
+		case *SpoilerSegment:
+			fmt.Fprintf(&b, "spoiler %q\n", s.Text)
+		case *EmojiSegment:
+			fmt.Fprintf(&b, "emoji %s %q\n", s.ID, s.Name)
+		default:
+			fmt.Fprintf(&b, "unknown %T\n", s)
+		}
+	}
+	return b.String()
+}
+
+// styleName names the predefined styles and describes inline variations.
+func styleName(style widget.RichTextStyle) string {
+	switch style {
+	case widget.RichTextStyleParagraph:
+		return "paragraph"
+	case widget.RichTextStyleBlockquote:
+		return "blockquote"
+	case widget.RichTextStyleCodeBlock:
+		return "codeblock"
+	case widget.RichTextStyleCodeInline:
+		return "code"
+	case widget.RichTextStyleHeading:
+		return "heading"
+	case widget.RichTextStyleSubHeading:
+		return "subheading"
+	case styleTimestamp:
+		return "timestamp"
+	}
+
+	name := "block"
+	if style.Inline {
+		name = "inline"
+	}
+	if style.TextStyle.Bold {
+		name += "+bold"
+	}
+	if style.TextStyle.Italic {
+		name += "+italic"
+	}
+	if style.ColorName != theme.ColorNameForeground {
+		name += "+" + string(style.ColorName)
+	}
+	return name
+}
@@ -0,0 +1,55 @@
+package markdown
+
+import (
+	"RGOClient/internal/context"
+)
+
+// Resolver looks up display names for referenced objects.
+// Methods return an empty string for unknown IDs.
+type Resolver interface {
+	UserName(userID string) string
+	ChannelName(channelID string) string
+	EmojiName(emojiID string) string
+}
+
+// SessionResolver resolves names from the global session state.
+type SessionResolver struct{}
+
+// UserName returns the username for a user ID.
+func (SessionResolver) UserName(userID string) string {
+	session := context.Session()
+	if session == nil {
+		return ""
+	}
+
+	if user := session.State.User(userID); user != nil {
+		return user.Username
+	}
+	return ""
+}
+
+// ChannelName returns the name of a channel.
+func (SessionResolver) ChannelName(channelID string) string {
+	session := context.Session()
+	if session == nil {
+		return ""
+	}
+
+	if channel := session.State.Channel(channelID); channel != nil {
+		return channel.Name
+	}
+	return ""
+}
+
+// EmojiName returns the name of a custom emoji.
+func (SessionResolver) EmojiName(emojiID string) string {
+	session := context.Session()
+	if session == nil {
+		return ""
+	}
+
+	if emoji := session.State.Emoji(emojiID); emoji != nil {
+		return emoji.Name
+	}
+	return ""
+}
@@ -0,0 +1,144 @@
+package markdown
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	fyneTheme "fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"RGOClient/internal/cache"
+	"RGOClient/internal/ui/theme"
+	"RGOClient/internal/util"
+)
+
+// Compile-time interface assertions.
+var (
+	_ widget.RichTextSegment = (*SpoilerSegment)(nil)
+	_ widget.RichTextSegment = (*EmojiSegment)(nil)
+	_ fyne.Tappable          = (*spoiler)(nil)
+)
+
+// SpoilerSegment is inline text that stays hidden until tapped.
+type SpoilerSegment struct {
+	Text     string
+	Revealed bool
+}
+
+// Inline returns true as spoilers sit within a line.
+func (s *SpoilerSegment) Inline() bool { return true }
+
+// Textual returns the hidden text.
+func (s *SpoilerSegment) Textual() string { return s.Text }
+
+// Visual creates the spoiler widget.
+func (s *SpoilerSegment) Visual() fyne.CanvasObject { return newSpoiler(s) }
+
+// Update syncs the widget with the segment state.
+func (s *SpoilerSegment) Update(o fyne.CanvasObject) {
+	if w, ok := o.(*spoiler); ok {
+		w.segment = s
+		w.Refresh()
+	}
+}
+
+// Select is a no-op; spoilers are not selectable.
+func (s *SpoilerSegment) Select(_, _ fyne.Position) {}
+
+// SelectedText returns nothing; spoilers are not selectable.
+func (s *SpoilerSegment) SelectedText() string { return "" }
+
+// Unselect is a no-op; spoilers are not selectable.
+func (s *SpoilerSegment) Unselect() {}
+
+// spoiler draws a SpoilerSegment: a solid block until tapped, then the text on a subtle background.
+type spoiler struct {
+	widget.BaseWidget
+	segment *SpoilerSegment
+	bg      *canvas.Rectangle
+	text    *canvas.Text
+}
+
+func newSpoiler(segment *SpoilerSegment) *spoiler {
+	w := &spoiler{
+		segment: segment,
+		bg:      canvas.NewRectangle(theme.Colors.SpoilerBg),
+		text:    canvas.NewText(segment.Text, theme.Colors.TextPrimary),
+	}
+	w.bg.CornerRadius = 4
+	w.ExtendBaseWidget(w)
+	w.applyState()
+	return w
+}
+
+// CreateRenderer returns the renderer for the spoiler.
+func (w *spoiler) CreateRenderer() fyne.WidgetRenderer {
+	padded := container.New(layout.NewCustomPaddedLayout(0, 0, 2, 2), w.text)
+	return widget.NewSimpleRenderer(container.NewStack(w.bg, padded))
+}
+
+// Tapped reveals the spoiler.
+func (w *spoiler) Tapped(*fyne.PointEvent) {
+	if w.segment.Revealed {
+		return
+	}
+	w.segment.Revealed = true
+	w.Refresh()
+}
+
+// Refresh applies the revealed state.
+func (w *spoiler) Refresh() {
+	w.applyState()
+	w.BaseWidget.Refresh()
+}
+
+func (w *spoiler) applyState() {
+	w.text.Text = w.segment.Text
+	if w.segment.Revealed {
+		w.bg.FillColor = theme.Colors.SpoilerRevealedBg
+		w.text.Color = theme.Colors.TextPrimary
+	} else {
+		w.bg.FillColor = theme.Colors.SpoilerBg
+		w.text.Color = theme.Colors.SpoilerBg
+	}
+	w.bg.Refresh()
+	w.text.Refresh()
+}
+
+// EmojiSegment is an inline custom emoji image.
+type EmojiSegment struct {
+	ID   string
+	Name string
+}
+
+// Inline returns true as emoji sit within a line.
+func (e *EmojiSegment) Inline() bool { return true }
+
+// Textual returns the :name: form of the emoji.
+func (e *EmojiSegment) Textual() string { return fmt.Sprintf(":%s:", e.Name) }
+
+// Visual creates the emoji image, loading it through the image cache.
+func (e *EmojiSegment) Visual() fyne.CanvasObject {
+	size := fyne.NewSquareSize(fyneTheme.TextSize() + theme.Sizes.InlineEmojiPadding)
+	placeholder := canvas.NewRectangle(theme.Colors.ServerDefaultBg)
+	placeholder.SetMinSize(size)
+
+	imgContainer := container.NewGridWrap(size, placeholder)
+	cache.GetImageCache().LoadImageToContainer(e.ID, util.EmojiURL(e.ID), size, imgContainer, false, nil)
+	return imgContainer
+}
+
+// Update is a no-op; the emoji image does not change.
+func (e *EmojiSegment) Update(fyne.CanvasObject) {}
+
+// Select is a no-op; emoji are not selectable.
+func (e *EmojiSegment) Select(_, _ fyne.Position) {}
+
+// SelectedText returns nothing; emoji are not selectable.
+func (e *EmojiSegment) SelectedText() string { return "" }
+
+// Unselect is a no-op; emoji are not selectable.
+func (e *EmojiSegment) Unselect() {}
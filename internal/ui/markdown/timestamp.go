@@ -0,0 +1,111 @@
+package markdown
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// styleTimestamp highlights rendered <t:...> timestamps.
+var styleTimestamp = widget.RichTextStyle{
+	ColorName: theme.ColorNamePrimary,
+	Inline:    true,
+	SizeName:  theme.SizeNameText,
+}
+
+// Layouts for the timestamp format letters, following Discord's convention.
+var timestampLayouts = map[string]string{
+	"t": "3:04 PM",
+	"T": "3:04:05 PM",
+	"d": "02/01/2006",
+	"D": "2 January 2006",
+	"f": "2 January 2006 3:04 PM",
+	"F": "Monday, 2 January 2006 3:04 PM",
+}
+
+// timestamp parses <t:unix> and <t:unix:format>.
+func (p *inlineParser) timestamp(s string) int {
+	end := strings.IndexByte(s, '>')
+	if end < 0 {
+		return 0
+	}
+
+	value, format, _ := strings.Cut(s[3:end], ":")
+	if format == "" {
+		format = "f"
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	text, ok := p.renderer.formatTimestamp(time.Unix(seconds, 0), format)
+	if !ok {
+		return 0
+	}
+
+	p.emit(&widget.TextSegment{Style: styleTimestamp, Text: text})
+	return end + 1
+}
+
+// formatTimestamp formats t for the given format letter. Returns false for unknown formats.
+func (r *Renderer) formatTimestamp(t time.Time, format string) (string, bool) {
+	location := r.Location
+	if location == nil {
+		location = time.Local
+	}
+
+	if format == "R" {
+		now := time.Now
+		if r.Now != nil {
+			now = r.Now
+		}
+		return relativeTime(t, now()), true
+	}
+
+	layout, ok := timestampLayouts[format]
+	if !ok {
+		return "", false
+	}
+	return t.In(location).Format(layout), true
+}
+
+// relativeTime describes t relative to now, e.g. "in 5 minutes" or "3 days ago".
+func relativeTime(t, now time.Time) string {
+	diff := now.Sub(t)
+	future := diff < 0
+	if future {
+		diff = -diff
+	}
+
+	var count int
+	var unit string
+	switch {
+	case diff < time.Minute:
+		count, unit = int(diff.Seconds()), "second"
+	case diff < time.Hour:
+		count, unit = int(diff.Minutes()), "minute"
+	case diff < 24*time.Hour:
+		count, unit = int(diff.Hours()), "hour"
+	case diff < 30*24*time.Hour:
+		count, unit = int(diff.Hours()/24), "day"
+	case diff < 365*24*time.Hour:
+		count, unit = int(diff.Hours()/(24*30)), "month"
+	default:
+		count, unit = int(diff.Hours()/(24*365)), "year"
+	}
+
+	if count != 1 {
+		unit += "s"
+	}
+
+	if future {
+		return fmt.Sprintf("in %d %s", count, unit)
+	}
+	return fmt.Sprintf("%d %s ago", count, unit)
+}
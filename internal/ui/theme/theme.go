@@ -11,16 +11,17 @@ import (
 // Centralizing colors makes it easy to maintain consistency and support theming.
 var Colors = struct {
 	// Backgrounds
-	ServerListBackground   color.Color
-	ChannelListBackground  color.Color
-	MessageAreaBackground  color.Color
-	MessageHoverBackground color.Color
-	ChannelHoverBackground color.Color
-	ChannelSelectedBg      color.Color
-	ServerDefaultBg        color.Color
-	ServerHoverBg          color.Color
-	ServerSelectedBg       color.Color
-	TappableHoverBg        color.Color
+	ServerListBackground       color.Color
+	ChannelListBackground      color.Color
+	MessageAreaBackground      color.Color
+	MessageHoverBackground     color.Color
+	MessageHighlightBackground color.Color
+	ChannelHoverBackground     color.Color
+	ChannelSelectedBg          color.Color
+	ServerDefaultBg            color.Color
+	ServerHoverBg              color.Color
+	ServerSelectedBg           color.Color
+	TappableHoverBg            color.Color
 
 	// Elements
 	AvatarPlaceholder  color.Color
@@ -37,21 +38,55 @@ var Colors = struct {
 	SwiftActionBg      color.Color
 	SwiftActionHoverBg color.Color
 	SwiftActionText    color.Color
+
+	// Reactions
+	ReactionBg         color.Color
+	ReactionHoverBg    color.Color
+	ReactionSelectedBg color.Color
+	ReactionSelected   color.Color
+
+	// Markdown
+	SpoilerBg         color.Color
+	SpoilerRevealedBg color.Color
+
+	// Profile
+	ProfileCardBg   color.Color
+	ProfileBannerBg color.Color
+	ProfileChipBg   color.Color
+	PresenceOnline  color.Color
+	PresenceIdle    color.Color
+	PresenceFocus   color.Color
+	PresenceBusy    color.Color
+	PresenceOffline color.Color
+
+	// Friends
+	FriendStatusError   color.Color
+	FriendStatusSuccess color.Color
+
+	// Connection
+	ReconnectBannerBg   color.Color
+	ReconnectBannerText color.Color
+
+	// Outbox
+	PendingMessageText   color.Color
+	PendingMessageFailed color.Color
+	PendingMessageDim    color.Color // Covers a message the server has not confirmed yet
 }{
 	// Backgrounds
-	ServerListBackground:   color.RGBA{R: 20, G: 20, B: 20, A: 255},
-	ChannelListBackground:  color.RGBA{R: 44, G: 44, B: 44, A: 255},
-	MessageAreaBackground:  color.RGBA{R: 28, G: 28, B: 28, A: 255},
-	MessageHoverBackground: color.RGBA{R: 45, G: 45, B: 45, A: 255},
-	ChannelHoverBackground: color.RGBA{R: 60, G: 60, B: 60, A: 255},
-	ChannelSelectedBg:      color.RGBA{R: 80, G: 80, B: 80, A: 255},
-	ServerDefaultBg:        color.RGBA{R: 60, G: 60, B: 60, A: 255},
-	ServerHoverBg:          color.RGBA{R: 80, G: 80, B: 80, A: 255},
-	ServerSelectedBg:       color.RGBA{R: 114, G: 137, B: 218, A: 255}, // "Blurple"
-	TappableHoverBg:        color.RGBA{R: 70, G: 70, B: 70, A: 255},
-	SwiftActionBg:          color.RGBA{R: 50, G: 50, B: 50, A: 255},
-	SwiftActionHoverBg:     color.RGBA{R: 80, G: 80, B: 80, A: 255},
-	SwiftActionText:        color.RGBA{R: 200, G: 200, B: 200, A: 255},
+	ServerListBackground:       color.RGBA{R: 20, G: 20, B: 20, A: 255},
+	ChannelListBackground:      color.RGBA{R: 44, G: 44, B: 44, A: 255},
+	MessageAreaBackground:      color.RGBA{R: 28, G: 28, B: 28, A: 255},
+	MessageHoverBackground:     color.RGBA{R: 45, G: 45, B: 45, A: 255},
+	MessageHighlightBackground: color.RGBA{R: 70, G: 60, B: 25, A: 255},
+	ChannelHoverBackground:     color.RGBA{R: 60, G: 60, B: 60, A: 255},
+	ChannelSelectedBg:          color.RGBA{R: 80, G: 80, B: 80, A: 255},
+	ServerDefaultBg:            color.RGBA{R: 60, G: 60, B: 60, A: 255},
+	ServerHoverBg:              color.RGBA{R: 80, G: 80, B: 80, A: 255},
+	ServerSelectedBg:           color.RGBA{R: 114, G: 137, B: 218, A: 255}, // "Blurple"
+	TappableHoverBg:            color.RGBA{R: 70, G: 70, B: 70, A: 255},
+	SwiftActionBg:              color.RGBA{R: 50, G: 50, B: 50, A: 255},
+	SwiftActionHoverBg:         color.RGBA{R: 80, G: 80, B: 80, A: 255},
+	SwiftActionText:            color.RGBA{R: 200, G: 200, B: 200, A: 255},
 
 	// Elements
 	AvatarPlaceholder: color.RGBA{R: 100, G: 100, B: 200, A: 255},
@@ -65,6 +100,39 @@ var Colors = struct {
 	XButtonNormal:     color.RGBA{R: 150, G: 150, B: 150, A: 255},
 	XButtonHover:      color.RGBA{R: 255, G: 100, B: 100, A: 255},
 	SessionCardBg:     color.RGBA{R: 50, G: 50, B: 50, A: 255},
+
+	// Reactions
+	ReactionBg:         color.RGBA{R: 40, G: 40, B: 40, A: 255},
+	ReactionHoverBg:    color.RGBA{R: 60, G: 60, B: 60, A: 255},
+	ReactionSelectedBg: color.RGBA{R: 45, G: 50, B: 75, A: 255},
+	ReactionSelected:   color.RGBA{R: 114, G: 137, B: 218, A: 255},
+
+	// Markdown
+	SpoilerBg:         color.RGBA{R: 15, G: 15, B: 15, A: 255},
+	SpoilerRevealedBg: color.RGBA{R: 55, G: 55, B: 55, A: 255},
+
+	// Profile
+	ProfileCardBg:   color.RGBA{R: 36, G: 36, B: 36, A: 255},
+	ProfileBannerBg: color.RGBA{R: 114, G: 137, B: 218, A: 255},
+	ProfileChipBg:   color.RGBA{R: 50, G: 50, B: 50, A: 255},
+	PresenceOnline:  color.RGBA{R: 59, G: 165, B: 93, A: 255},
+	PresenceIdle:    color.RGBA{R: 250, G: 166, B: 26, A: 255},
+	PresenceFocus:   color.RGBA{R: 77, G: 145, B: 247, A: 255},
+	PresenceBusy:    color.RGBA{R: 237, G: 66, B: 69, A: 255},
+	PresenceOffline: color.RGBA{R: 116, G: 127, B: 141, A: 255},
+
+	// Friends
+	FriendStatusError:   color.RGBA{R: 237, G: 66, B: 69, A: 255},
+	FriendStatusSuccess: color.RGBA{R: 59, G: 165, B: 93, A: 255},
+
+	// Connection
+	ReconnectBannerBg:   color.RGBA{R: 250, G: 166, B: 26, A: 255},
+	ReconnectBannerText: color.RGBA{R: 32, G: 34, B: 37, A: 255},
+
+	// Outbox
+	PendingMessageText:   color.RGBA{R: 148, G: 155, B: 164, A: 255},
+	PendingMessageFailed: color.RGBA{R: 237, G: 66, B: 69, A: 255},
+	PendingMessageDim:    color.NRGBA{R: 28, G: 28, B: 28, A: 120},
 }
 
 // Sizes defines standard sizes used throughout the application.
@@ -98,10 +166,49 @@ var Sizes = struct {
 	MessageTextLeftPadding    float32
 	MessageTimestampSize      float32
 	MessageTimestampTopOffset float32
+	TypingIndicatorHeight     float32
+	TypingIndicatorTextSize   float32
 
 	// Swift Actions
 	SwiftActionSize float32
 
+	// Reactions
+	ReactionHeight    float32
+	ReactionEmojiSize float32
+	ReactionCountSize float32
+
+	// Markdown
+	InlineEmojiPadding float32
+
+	// Emoji picker
+	EmojiPickerWidth     float32
+	EmojiPickerHeight    float32
+	EmojiPickerCellSize  float32
+	EmojiPickerEmojiSize float32
+	EmojiButtonSize      float32
+
+	// Profile
+	ProfileCardWidth    float32
+	ProfileCardHeight   float32
+	ProfileBannerHeight float32
+	ProfileAvatarSize   float32
+	PresenceDotSize     float32
+
+	// Direct messages
+	DirectMessageItemHeight float32
+	DirectMessageAvatarSize float32
+
+	// Friends
+	FriendItemHeight float32
+	FriendAvatarSize float32
+
+	// Member list
+	MemberListWidth    float32
+	MemberItemHeight   float32
+	MemberHeaderHeight float32
+	MemberAvatarSize   float32
+	MemberToggleSize   float32
+
 	// Session/Login
 	SessionCardAvatarSize float32
 	XButtonSize           float32 // todo: remove?
@@ -115,6 +222,9 @@ var Sizes = struct {
 	ImageViewerMaxHeight float32
 	ImageViewerMinWidth  float32
 	ImageViewerMinHeight float32
+
+	// Dialogs
+	DeleteDialogWidth float32
 }{
 	// Sidebar
 	ServerSidebarWidth:    60,
@@ -145,10 +255,49 @@ var Sizes = struct {
 	MessageTextLeftPadding:    4,
 	MessageTimestampSize:      12,
 	MessageTimestampTopOffset: 4,
+	TypingIndicatorHeight:     18,
+	TypingIndicatorTextSize:   12,
 
 	// Swift Actions
 	SwiftActionSize: 32,
 
+	// Reactions
+	ReactionHeight:    26,
+	ReactionEmojiSize: 16,
+	ReactionCountSize: 12,
+
+	// Markdown
+	InlineEmojiPadding: 6,
+
+	// Emoji picker
+	EmojiPickerWidth:     360,
+	EmojiPickerHeight:    400,
+	EmojiPickerCellSize:  36,
+	EmojiPickerEmojiSize: 22,
+	EmojiButtonSize:      22,
+
+	// Profile
+	ProfileCardWidth:    340,
+	ProfileCardHeight:   480,
+	ProfileBannerHeight: 100,
+	ProfileAvatarSize:   72,
+	PresenceDotSize:     10,
+
+	// Direct messages
+	DirectMessageItemHeight: 44,
+	DirectMessageAvatarSize: 32,
+
+	// Friends
+	FriendItemHeight: 56,
+	FriendAvatarSize: 36,
+
+	// Member list
+	MemberListWidth:    240,
+	MemberItemHeight:   42,
+	MemberHeaderHeight: 32,
+	MemberAvatarSize:   32,
+	MemberToggleSize:   20,
+
 	// Session/Login
 	SessionCardAvatarSize: 32,
 	XButtonSize:           24,
@@ -162,6 +311,9 @@ var Sizes = struct {
 	ImageViewerMaxHeight: 800,
 	ImageViewerMinWidth:  400,
 	ImageViewerMinHeight: 300,
+
+	// Dialogs
+	DeleteDialogWidth: 480,
 }
 
 // NoScrollTheme hides scrollbars for a cleaner look.
@@ -0,0 +1,85 @@
+package widgets
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/desktop"
+	fyneTheme "fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"RGOClient/internal/ui/theme"
+)
+
+// Compile-time interface assertions.
+var (
+	_ widget.RichTextSegment = (*authorSegment)(nil)
+	_ fyne.Tappable          = (*authorName)(nil)
+	_ desktop.Cursorable     = (*authorName)(nil)
+)
+
+// authorSegment is the bold, tappable author name at the start of a message.
+type authorSegment struct {
+	Name     string
+	OnTapped func()
+}
+
+// Inline returns true so the message text follows on the next paragraph as before.
+func (s *authorSegment) Inline() bool { return true }
+
+// Textual returns the author name.
+func (s *authorSegment) Textual() string { return s.Name }
+
+// Visual creates the author name widget.
+func (s *authorSegment) Visual() fyne.CanvasObject { return newAuthorName(s) }
+
+// Update syncs the widget with the segment.
+func (s *authorSegment) Update(o fyne.CanvasObject) {
+	if w, ok := o.(*authorName); ok {
+		w.segment = s
+		w.text.Text = s.Name
+		w.Refresh()
+	}
+}
+
+// Select is a no-op; the author name is not selectable.
+func (s *authorSegment) Select(_, _ fyne.Position) {}
+
+// SelectedText returns nothing; the author name is not selectable.
+func (s *authorSegment) SelectedText() string { return "" }
+
+// Unselect is a no-op; the author name is not selectable.
+func (s *authorSegment) Unselect() {}
+
+// authorName draws an authorSegment.
+type authorName struct {
+	widget.BaseWidget
+	segment *authorSegment
+	text    *canvas.Text
+}
+
+func newAuthorName(segment *authorSegment) *authorName {
+	text := canvas.NewText(segment.Name, theme.Colors.TextPrimary)
+	text.TextSize = fyneTheme.TextSize()
+	text.TextStyle = fyne.TextStyle{Bold: true}
+
+	w := &authorName{segment: segment, text: text}
+	w.ExtendBaseWidget(w)
+	return w
+}
+
+// CreateRenderer returns the widget renderer.
+func (w *authorName) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(w.text)
+}
+
+// Tapped opens the author's profile.
+func (w *authorName) Tapped(*fyne.PointEvent) {
+	if w.segment.OnTapped != nil {
+		w.segment.OnTapped()
+	}
+}
+
+// Cursor shows a pointer to hint that the name is clickable.
+func (w *authorName) Cursor() desktop.Cursor {
+	return desktop.PointerCursor
+}
@@ -78,8 +78,8 @@ type ClickableAvatar struct {
 }
 
 // NewClickableAvatar creates a clickable avatar widget.
-// Loads avatar asynchronously if avatarID and avatarURL are provided.
-func NewClickableAvatar(avatarID, avatarURL, userID string, onTapped func()) *ClickableAvatar {
+// Loads avatar asynchronously for owner if avatarID and avatarURL are provided.
+func NewClickableAvatar(owner cache.ImageOwner, avatarID, avatarURL, userID string, onTapped func()) *ClickableAvatar {
 	size := fyne.NewSize(theme.Sizes.MessageAvatarSize, theme.Sizes.MessageAvatarSize)
 
 	// Circular placeholder
@@ -88,7 +88,7 @@ func NewClickableAvatar(avatarID, avatarURL, userID string, onTapped func()) *Cl
 
 	// Load avatar asynchronously
 	if avatarURL != "" && avatarID != "" {
-		cache.GetImageCache().LoadImageToContainer(avatarID, avatarURL, size, content, true, nil)
+		owner.LoadImageToContainer(avatarID, avatarURL, size, content, true, nil)
 	}
 
 	a := &ClickableAvatar{
@@ -0,0 +1,166 @@
+package widgets
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"github.com/sentinelb51/revoltgo"
+
+	"RGOClient/internal/cache"
+	"RGOClient/internal/ui/theme"
+	"RGOClient/internal/util"
+)
+
+// Compile-time interface assertions.
+var (
+	_ fyne.Widget       = (*DirectMessageWidget)(nil)
+	_ fyne.Tappable     = (*DirectMessageWidget)(nil)
+	_ desktop.Hoverable = (*DirectMessageWidget)(nil)
+)
+
+// DirectMessageInfo describes a DM or group entry in the Home channel list.
+type DirectMessageInfo struct {
+	ChannelID string
+	Name      string // Recipient name, or group name
+	Subtitle  string // Custom status for DMs, member count for groups
+	AvatarURL string // Recipient avatar, or group icon
+	IconPath  string // Static icon instead of an avatar (Friends entry)
+	Presence  revoltgo.UserStatusPresence
+	Online    bool
+	IsGroup   bool // Groups (and static icons) show no presence dot
+}
+
+// DirectMessageWidget displays a DM or group channel with avatar, presence, selection and unread state.
+type DirectMessageWidget struct {
+	widget.BaseWidget
+	Info  DirectMessageInfo
+	onTap func()
+
+	background      *canvas.Rectangle
+	unreadIndicator *canvas.Rectangle
+	label           *canvas.Text
+
+	selected bool
+	unread   bool
+}
+
+// NewDirectMessageWidget creates a DM list entry.
+func NewDirectMessageWidget(info DirectMessageInfo, onTap func()) *DirectMessageWidget {
+	w := &DirectMessageWidget{
+		Info:            info,
+		onTap:           onTap,
+		background:      canvas.NewRectangle(color.Transparent),
+		unreadIndicator: canvas.NewRectangle(color.Transparent),
+		label:           canvas.NewText(info.Name, theme.Colors.CategoryText),
+	}
+	w.label.TextSize = theme.Sizes.MessageTimestampSize + 2 // Same as channel names
+	w.ExtendBaseWidget(w)
+	return w
+}
+
+// SetState updates the selection and unread state together.
+func (w *DirectMessageWidget) SetState(selected, unread bool) {
+	w.selected = selected
+	w.unread = unread
+	w.updateAppearance()
+}
+
+func (w *DirectMessageWidget) updateAppearance() {
+	if w.selected {
+		w.background.FillColor = theme.Colors.ChannelSelectedBg
+	} else {
+		w.background.FillColor = color.Transparent
+	}
+	w.background.Refresh()
+
+	if w.unread {
+		w.unreadIndicator.FillColor = theme.Colors.UnreadIndicator
+	} else {
+		w.unreadIndicator.FillColor = color.Transparent
+	}
+	w.unreadIndicator.Refresh()
+
+	if w.selected || w.unread {
+		w.label.Color = theme.Colors.TextPrimary
+	} else {
+		w.label.Color = theme.Colors.CategoryText
+	}
+	w.label.Refresh()
+}
+
+// CreateRenderer returns the renderer for this widget.
+func (w *DirectMessageWidget) CreateRenderer() fyne.WidgetRenderer {
+	avatarSize := fyne.NewSquareSize(theme.Sizes.DirectMessageAvatarSize)
+	placeholder := canvas.NewCircle(theme.Colors.AvatarPlaceholder)
+	avatar := container.NewGridWrap(avatarSize, placeholder)
+
+	if w.Info.IconPath != "" {
+		icon := canvas.NewImageFromFile(w.Info.IconPath)
+		icon.FillMode = canvas.ImageFillContain
+		icon.SetMinSize(avatarSize.Subtract(fyne.NewSquareSize(theme.Sizes.DirectMessageAvatarSize / 3)))
+		avatar.Objects = []fyne.CanvasObject{container.NewCenter(icon)}
+	} else if url := w.Info.AvatarURL; url != "" {
+		avatarID := util.IDFromAttachmentURL(url)
+		if avatarID == "" {
+			avatarID = url
+		}
+		cache.GetImageCache().LoadImageToContainer(avatarID, url, avatarSize, avatar, true, nil)
+	}
+
+	avatarStack := container.NewStack(avatar)
+	if !w.Info.IsGroup && w.Info.IconPath == "" {
+		presenceColour, _ := presenceStyle(w.Info.Presence, w.Info.Online)
+		dot := container.NewGridWrap(fyne.NewSquareSize(theme.Sizes.PresenceDotSize), canvas.NewCircle(presenceColour))
+		avatarStack.Add(container.NewVBox(layout.NewSpacer(), container.NewHBox(layout.NewSpacer(), dot)))
+	}
+
+	text := container.NewVBox(layout.NewSpacer(), w.label)
+	if w.Info.Subtitle != "" {
+		subtitle := canvas.NewText(w.Info.Subtitle, theme.Colors.TimestampText)
+		subtitle.TextSize = theme.Sizes.MessageTimestampSize
+		text.Add(subtitle)
+	}
+	text.Add(layout.NewSpacer())
+
+	w.unreadIndicator.SetMinSize(fyne.NewSize(theme.Sizes.UnreadIndicatorWidth, 0))
+	w.background.SetMinSize(fyne.NewSize(0, theme.Sizes.DirectMessageItemHeight))
+
+	content := HBoxNoSpacing(
+		container.NewHBox(w.unreadIndicator),
+		HorizontalSpacer(theme.Sizes.ChannelLeftPadding),
+		container.NewCenter(avatarStack),
+		HorizontalSpacer(8),
+		text,
+	)
+
+	w.updateAppearance()
+	return widget.NewSimpleRenderer(container.NewStack(w.background, content))
+}
+
+// Tapped handles tap events on the widget.
+func (w *DirectMessageWidget) Tapped(*fyne.PointEvent) {
+	if w.onTap != nil {
+		w.onTap()
+	}
+}
+
+// MouseIn handles mouse entering the widget.
+func (w *DirectMessageWidget) MouseIn(*desktop.MouseEvent) {
+	if !w.selected {
+		w.background.FillColor = theme.Colors.ChannelHoverBackground
+		w.background.Refresh()
+	}
+}
+
+// MouseMoved handles mouse movement within the widget.
+func (w *DirectMessageWidget) MouseMoved(*desktop.MouseEvent) {}
+
+// MouseOut handles mouse leaving the widget.
+func (w *DirectMessageWidget) MouseOut() {
+	w.updateAppearance()
+}
@@ -0,0 +1,190 @@
+package widgets
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"RGOClient/internal/cache"
+	"RGOClient/internal/emoji"
+	"RGOClient/internal/ui/theme"
+	"RGOClient/internal/util"
+)
+
+// Compile-time interface assertion.
+var _ fyne.Widget = (*EmojiPicker)(nil)
+
+// EmojiPicker is a searchable grid of recent, custom and Unicode emoji.
+// Its sections are built once; searching shows cells reused between keystrokes.
+type EmojiPicker struct {
+	widget.BaseWidget
+	search   *widget.Entry
+	body     *fyne.Container
+	sections []fyne.CanvasObject          // Titles and grids of every section
+	pool     []emoji.Emoji                // Offered emoji, searched by the query
+	results  *fyne.Container              // Grid of search results
+	cells    map[string]fyne.CanvasObject // Result cells by emoji value
+	allowed  func(emoji.Emoji) bool
+	onPick   func(emoji.Emoji)
+}
+
+// NewEmojiPicker creates a picker. custom holds server emoji grouped by server.
+// allowed filters the offered emoji (nil allows all).
+func NewEmojiPicker(custom []emoji.Category, allowed func(emoji.Emoji) bool, onPick func(emoji.Emoji)) *EmojiPicker {
+	w := &EmojiPicker{
+		search:  widget.NewEntry(),
+		body:    container.NewVBox(),
+		results: container.NewGridWrap(fyne.NewSquareSize(theme.Sizes.EmojiPickerCellSize)),
+		cells:   make(map[string]fyne.CanvasObject),
+		allowed: allowed,
+		onPick:  onPick,
+	}
+
+	seen := make(map[string]bool)
+	w.addSection("Recently used", emoji.Recent())
+	for _, category := range custom {
+		w.addSection(category.Name, category.Emoji)
+		w.addToPool(category.Emoji, seen)
+	}
+	for _, category := range emoji.Categories() {
+		w.addSection(category.Name, category.Emoji)
+		w.addToPool(category.Emoji, seen)
+	}
+
+	w.search.SetPlaceHolder("Search emoji...")
+	w.search.OnChanged = func(string) { w.filter() }
+	w.ExtendBaseWidget(w)
+	w.filter()
+	return w
+}
+
+// CreateRenderer returns the renderer for the picker.
+func (w *EmojiPicker) CreateRenderer() fyne.WidgetRenderer {
+	bg := canvas.NewRectangle(theme.Colors.ChannelListBackground)
+	bg.CornerRadius = 8
+
+	content := container.NewBorder(w.search, nil, nil, nil, container.NewVScroll(w.body))
+	return widget.NewSimpleRenderer(container.NewStack(bg, container.NewPadded(content)))
+}
+
+// MinSize returns the fixed picker size.
+func (w *EmojiPicker) MinSize() fyne.Size {
+	return fyne.NewSize(theme.Sizes.EmojiPickerWidth, theme.Sizes.EmojiPickerHeight)
+}
+
+// filter shows search results, or all sections when the search is empty.
+func (w *EmojiPicker) filter() {
+	query := w.search.Text
+	if query == "" {
+		w.body.Objects = w.sections
+		w.body.Refresh()
+		return
+	}
+
+	matches := emoji.Search(query, w.pool, 0)
+	cells := make([]fyne.CanvasObject, 0, len(matches))
+	for _, e := range matches {
+		cell := w.cells[e.Value]
+		if cell == nil {
+			cell = w.newCell(e)
+			w.cells[e.Value] = cell
+		}
+		cells = append(cells, cell)
+	}
+	w.results.Objects = cells
+	w.results.Refresh()
+
+	w.body.Objects = nil
+	if len(cells) > 0 {
+		w.body.Objects = []fyne.CanvasObject{sectionTitle("Results"), w.results}
+	}
+	w.body.Refresh()
+}
+
+// addSection adds a titled grid of emoji, skipping sections left empty by allowed.
+func (w *EmojiPicker) addSection(title string, list []emoji.Emoji) {
+	grid := container.NewGridWrap(fyne.NewSquareSize(theme.Sizes.EmojiPickerCellSize))
+	for _, e := range list {
+		if w.allowed == nil || w.allowed(e) {
+			grid.Add(w.newCell(e))
+		}
+	}
+
+	if len(grid.Objects) > 0 {
+		w.sections = append(w.sections, sectionTitle(title), grid)
+	}
+}
+
+// addToPool adds the allowed emoji of a list to the searched ones, skipping values already seen.
+func (w *EmojiPicker) addToPool(list []emoji.Emoji, seen map[string]bool) {
+	for _, e := range list {
+		if seen[e.Value] || (w.allowed != nil && !w.allowed(e)) {
+			continue
+		}
+		seen[e.Value] = true
+		w.pool = append(w.pool, e)
+	}
+}
+
+// newCell creates the tappable cell of an emoji.
+func (w *EmojiPicker) newCell(e emoji.Emoji) fyne.CanvasObject {
+	return NewTappableContainer(container.NewCenter(NewEmoji(e.Value, theme.Sizes.EmojiPickerEmojiSize)), func() {
+		emoji.RecordUse(e)
+		if w.onPick != nil {
+			w.onPick(e)
+		}
+	})
+}
+
+// sectionTitle creates the heading of a picker section.
+func sectionTitle(title string) fyne.CanvasObject {
+	label := canvas.NewText(title, theme.Colors.CategoryText)
+	label.TextSize = 12
+	label.TextStyle = fyne.TextStyle{Bold: true}
+	return label
+}
+
+// NewEmoji renders a Unicode emoji as text, or loads a custom emoji image by ID.
+func NewEmoji(emojiID string, size float32) fyne.CanvasObject {
+	if !util.IsCustomEmoji(emojiID) {
+		text := canvas.NewText(emojiID, theme.Colors.TextPrimary)
+		text.TextSize = size
+		return text
+	}
+
+	emojiSize := fyne.NewSize(size, size)
+	placeholder := canvas.NewRectangle(theme.Colors.ServerDefaultBg)
+	placeholder.SetMinSize(emojiSize)
+	imgContainer := container.NewGridWrap(emojiSize, placeholder)
+	cache.GetImageCache().LoadImageToContainer(emojiID, util.EmojiURL(emojiID), emojiSize, imgContainer, false, nil)
+	return imgContainer
+}
+
+// ShowEmojiPicker opens a picker popup above the given object.
+// The popup closes after an emoji is picked.
+func ShowEmojiPicker(anchor fyne.CanvasObject, custom []emoji.Category, allowed func(emoji.Emoji) bool, onPick func(emoji.Emoji)) {
+	driver := fyne.CurrentApp().Driver()
+	c := driver.CanvasForObject(anchor)
+	if c == nil {
+		return
+	}
+
+	var popup *widget.PopUp
+	picker := NewEmojiPicker(custom, allowed, func(e emoji.Emoji) {
+		popup.Hide()
+		if onPick != nil {
+			onPick(e)
+		}
+	})
+	popup = widget.NewPopUp(picker, c)
+
+	// Open above the anchor, right-aligned with it, clamped to the window
+	size := popup.MinSize()
+	pos := driver.AbsolutePositionForObject(anchor)
+	x := max(0, min(pos.X+anchor.Size().Width-size.Width, c.Size().Width-size.Width))
+	y := max(0, pos.Y-size.Height)
+
+	popup.ShowAtPosition(fyne.NewPos(x, y))
+	c.Focus(picker.search)
+}
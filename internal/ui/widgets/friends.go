@@ -0,0 +1,322 @@
+package widgets
+
+import (
+	"cmp"
+	"fmt"
+	"image/color"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"github.com/sentinelb51/revoltgo"
+
+	"RGOClient/internal/cache"
+	"RGOClient/internal/ui/theme"
+	"RGOClient/internal/util"
+)
+
+// Compile-time interface assertions.
+var _ fyne.Widget = (*FriendsPanel)(nil)
+
+// FriendsTab selects which relationships the friends panel lists.
+type FriendsTab int
+
+const (
+	FriendsTabOnline FriendsTab = iota
+	FriendsTabAll
+	FriendsTabPending
+	FriendsTabBlocked
+)
+
+// FriendRow describes a user the current user has a relationship with.
+type FriendRow struct {
+	UserID       string
+	Name         string // Display name, or username
+	Username     string // Full username ("name#1234")
+	AvatarURL    string
+	Presence     revoltgo.UserStatusPresence
+	Online       bool
+	Relationship revoltgo.UserRelationshipType
+}
+
+// FriendActions are the callbacks of the friends panel.
+// OnRemove removes a friend, denies an incoming request or cancels an outgoing one.
+type FriendActions struct {
+	OnSendRequest func(username string)
+	OnAccept      func(userID string)
+	OnRemove      func(userID string)
+	OnBlock       func(userID string)
+	OnUnblock     func(userID string)
+	OnMessage     func(userID string)
+	OnProfile     func(userID string)
+}
+
+// FriendsPanel lists friends, pending requests and blocked users in tabs,
+// with a form for sending friend requests by username.
+type FriendsPanel struct {
+	widget.BaseWidget
+	Actions FriendActions
+
+	tab     FriendsTab
+	rows    []FriendRow
+	tabs    *fyne.Container
+	list    *fyne.Container
+	entry   *widget.Entry
+	status  *canvas.Text
+	content fyne.CanvasObject
+}
+
+// NewFriendsPanel creates an empty friends panel on the Online tab.
+func NewFriendsPanel(actions FriendActions) *FriendsPanel {
+	w := &FriendsPanel{
+		Actions: actions,
+		tabs:    container.NewHBox(),
+		list:    container.NewVBox(),
+		entry:   widget.NewEntry(),
+		status:  canvas.NewText("", theme.Colors.TimestampText),
+	}
+	w.status.TextSize = 12
+	w.status.Hide()
+
+	w.entry.SetPlaceHolder("Add friend by username (name#1234)")
+	w.entry.OnSubmitted = func(string) { w.submitRequest() }
+	send := widget.NewButton("Send request", w.submitRequest)
+	send.Importance = widget.HighImportance
+
+	form := container.NewVBox(
+		container.NewBorder(nil, nil, nil, send, w.entry),
+		w.status,
+	)
+
+	w.content = container.NewBorder(
+		container.NewVBox(container.NewPadded(w.tabs), container.NewPadded(form), widget.NewSeparator()),
+		nil, nil, nil,
+		container.NewVScroll(container.NewPadded(w.list)),
+	)
+
+	w.rebuild()
+	w.ExtendBaseWidget(w)
+	return w
+}
+
+// CreateRenderer returns the widget renderer.
+func (w *FriendsPanel) CreateRenderer() fyne.WidgetRenderer {
+	bg := canvas.NewRectangle(theme.Colors.MessageAreaBackground)
+	return widget.NewSimpleRenderer(container.NewStack(bg, w.content))
+}
+
+// SetRows replaces all relationships; each tab shows the matching subset.
+func (w *FriendsPanel) SetRows(rows []FriendRow) {
+	w.rows = rows
+	w.rebuild()
+}
+
+// SetTab switches the listed tab.
+func (w *FriendsPanel) SetTab(tab FriendsTab) {
+	w.tab = tab
+	w.rebuild()
+}
+
+// SetStatus shows the outcome of a friend request below the form; an empty text hides it.
+// A successful request also clears the entry.
+func (w *FriendsPanel) SetStatus(text string, isError bool) {
+	if text == "" {
+		w.status.Hide()
+		return
+	}
+
+	w.status.Text = text
+	if isError {
+		w.status.Color = theme.Colors.FriendStatusError
+	} else {
+		w.status.Color = theme.Colors.FriendStatusSuccess
+		w.entry.SetText("")
+	}
+	w.status.Show()
+	w.status.Refresh()
+}
+
+// incomingCount returns the number of pending incoming requests.
+func (w *FriendsPanel) incomingCount() int {
+	count := 0
+	for _, row := range w.rows {
+		if row.Relationship == revoltgo.UserRelationsTypeIncoming {
+			count++
+		}
+	}
+	return count
+}
+
+func (w *FriendsPanel) submitRequest() {
+	username := strings.TrimSpace(w.entry.Text)
+	if username == "" || w.Actions.OnSendRequest == nil {
+		return
+	}
+	w.Actions.OnSendRequest(username)
+}
+
+// rebuild recreates the tab buttons and the rows of the current tab.
+func (w *FriendsPanel) rebuild() {
+	pending := "Pending"
+	if n := w.incomingCount(); n > 0 {
+		pending = fmt.Sprintf("Pending (%d)", n)
+	}
+
+	title := canvas.NewText("Friends", theme.Colors.TextPrimary)
+	title.TextStyle = fyne.TextStyle{Bold: true}
+
+	w.tabs.Objects = []fyne.CanvasObject{container.NewCenter(title), HorizontalSpacer(8)}
+	for i, label := range []string{"Online", "All", pending, "Blocked"} {
+		tab := FriendsTab(i)
+		button := widget.NewButton(label, func() { w.SetTab(tab) })
+		button.Importance = widget.LowImportance
+		if tab == w.tab {
+			button.Importance = widget.HighImportance
+		}
+		w.tabs.Add(button)
+	}
+	w.tabs.Refresh()
+
+	w.list.Objects = nil
+	switch w.tab {
+	case FriendsTabOnline:
+		w.addSection("Online", w.filter(func(r FriendRow) bool {
+			return r.Relationship == revoltgo.UserRelationsTypeFriend && isOnline(r)
+		}), "No friends are online.")
+	case FriendsTabAll:
+		w.addSection("All friends", w.filter(func(r FriendRow) bool {
+			return r.Relationship == revoltgo.UserRelationsTypeFriend
+		}), "No friends yet. Send a request by username above.")
+	case FriendsTabPending:
+		incoming := w.filter(func(r FriendRow) bool { return r.Relationship == revoltgo.UserRelationsTypeIncoming })
+		outgoing := w.filter(func(r FriendRow) bool { return r.Relationship == revoltgo.UserRelationsTypeOutgoing })
+		if len(incoming) == 0 && len(outgoing) == 0 {
+			w.addSection("Pending", nil, "No pending friend requests.")
+		} else {
+			w.addSection("Incoming", incoming, "")
+			w.addSection("Outgoing", outgoing, "")
+		}
+	case FriendsTabBlocked:
+		w.addSection("Blocked", w.filter(func(r FriendRow) bool {
+			return r.Relationship == revoltgo.UserRelationsTypeBlocked
+		}), "You haven't blocked anyone.")
+	}
+	w.list.Refresh()
+}
+
+// filter returns the matching rows sorted by name.
+func (w *FriendsPanel) filter(match func(FriendRow) bool) []FriendRow {
+	var rows []FriendRow
+	for _, row := range w.rows {
+		if match(row) {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortFunc(rows, func(a, b FriendRow) int {
+		return cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return rows
+}
+
+// addSection adds a "TITLE — N" header followed by the rows; empty sections show the empty text, if any.
+func (w *FriendsPanel) addSection(title string, rows []FriendRow, empty string) {
+	if len(rows) == 0 {
+		if empty != "" {
+			w.list.Add(container.NewCenter(profileText(empty, theme.Colors.TimestampText)))
+		}
+		return
+	}
+
+	header := canvas.NewText(fmt.Sprintf("%s — %d", strings.ToUpper(title), len(rows)), theme.Colors.CategoryText)
+	header.TextSize = 11
+	header.TextStyle = fyne.TextStyle{Bold: true}
+	w.list.Add(container.NewPadded(header))
+
+	for _, row := range rows {
+		w.list.Add(w.buildRow(row))
+	}
+}
+
+// buildRow creates a user row with the actions available for its relationship.
+func (w *FriendsPanel) buildRow(row FriendRow) fyne.CanvasObject {
+	avatarSize := fyne.NewSquareSize(theme.Sizes.FriendAvatarSize)
+	avatar := container.NewGridWrap(avatarSize, canvas.NewCircle(theme.Colors.AvatarPlaceholder))
+	if url := row.AvatarURL; url != "" {
+		avatarID := util.IDFromAttachmentURL(url)
+		if avatarID == "" {
+			avatarID = url
+		}
+		cache.GetImageCache().LoadImageToContainer(avatarID, url, avatarSize, avatar, true, nil)
+	}
+
+	presenceColour, presenceLabel := presenceStyle(row.Presence, row.Online)
+	dot := container.NewGridWrap(fyne.NewSquareSize(theme.Sizes.PresenceDotSize), canvas.NewCircle(presenceColour))
+	avatarStack := container.NewStack(avatar, container.NewVBox(layout.NewSpacer(), container.NewHBox(layout.NewSpacer(), dot)))
+
+	userID := row.UserID
+	tappableAvatar := NewTappableContainer(avatarStack, func() { w.call(w.Actions.OnProfile, userID) })
+
+	name := canvas.NewText(row.Name, theme.Colors.TextPrimary)
+	name.TextSize = 14
+	name.TextStyle = fyne.TextStyle{Bold: true}
+
+	subtitle := row.Username
+	switch row.Relationship {
+	case revoltgo.UserRelationsTypeFriend:
+		subtitle = fmt.Sprintf("%s • %s", row.Username, presenceLabel)
+	case revoltgo.UserRelationsTypeIncoming:
+		subtitle = fmt.Sprintf("%s • Incoming friend request", row.Username)
+	case revoltgo.UserRelationsTypeOutgoing:
+		subtitle = fmt.Sprintf("%s • Outgoing friend request", row.Username)
+	}
+	text := container.NewVBox(layout.NewSpacer(), name, profileText(subtitle, theme.Colors.TimestampText), layout.NewSpacer())
+
+	buttons := container.NewHBox()
+	addButton := func(label string, action func(string), importance widget.Importance) {
+		button := widget.NewButton(label, func() { w.call(action, userID) })
+		button.Importance = importance
+		buttons.Add(button)
+	}
+
+	switch row.Relationship {
+	case revoltgo.UserRelationsTypeFriend:
+		addButton("Message", w.Actions.OnMessage, widget.HighImportance)
+		addButton("Remove", w.Actions.OnRemove, widget.MediumImportance)
+		addButton("Block", w.Actions.OnBlock, widget.DangerImportance)
+	case revoltgo.UserRelationsTypeIncoming:
+		addButton("Accept", w.Actions.OnAccept, widget.SuccessImportance)
+		addButton("Deny", w.Actions.OnRemove, widget.MediumImportance)
+		addButton("Block", w.Actions.OnBlock, widget.DangerImportance)
+	case revoltgo.UserRelationsTypeOutgoing:
+		addButton("Cancel", w.Actions.OnRemove, widget.MediumImportance)
+		addButton("Block", w.Actions.OnBlock, widget.DangerImportance)
+	case revoltgo.UserRelationsTypeBlocked:
+		addButton("Unblock", w.Actions.OnUnblock, widget.MediumImportance)
+	}
+
+	bg := canvas.NewRectangle(color.Transparent)
+	bg.SetMinSize(fyne.NewSize(0, theme.Sizes.FriendItemHeight))
+
+	content := container.NewBorder(nil, nil,
+		HBoxNoSpacing(HorizontalSpacer(8), container.NewCenter(tappableAvatar), HorizontalSpacer(10)),
+		container.NewCenter(buttons),
+		text,
+	)
+	return container.NewStack(bg, content)
+}
+
+// call invokes an optional per-user callback.
+func (w *FriendsPanel) call(action func(string), userID string) {
+	if action != nil {
+		action(userID)
+	}
+}
+
+// isOnline reports whether a friend shows as online (invisible users appear offline).
+func isOnline(row FriendRow) bool {
+	return row.Online && row.Presence != revoltgo.UserStatusPresenceInvisible
+}
@@ -7,6 +7,7 @@ import (
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
 
 	appTheme "RGOClient/internal/ui/theme"
 	"RGOClient/internal/ui/widgets"
@@ -14,55 +15,178 @@ import (
 )
 
 // Attachment represents a file attached to the message.
+// Files are uploaded as soon as they are attached (see OnAttach); the card shows the upload's state.
 type Attachment struct {
 	Path string
 	Name string
+	Size int64
+	ID   string // Uploaded file ID; empty until uploaded
+	Err  error  // Why the upload failed; blocks sending until the file is removed or retried
+
+	// Cancel stops the upload. Set by OnAttach; called when the file is removed.
+	Cancel func()
+
+	progress float64
+	status   *widget.ProgressBar // Card widgets of the attachment, updated in place
+	error    *widget.Label
+	retry    *widget.Button
+}
+
+// Uploading reports whether the file is still being uploaded.
+func (a *Attachment) Uploading() bool {
+	return a.ID == "" && a.Err == nil
 }
 
 // AddAttachment adds a file to the attachment list and updates the UI.
 func (m *MessageInput) AddAttachment(path string) {
-	name := filepath.Base(path)
-	att := Attachment{Path: path, Name: name}
+	att := &Attachment{Path: path, Name: filepath.Base(path)}
+	if info, err := os.Stat(path); err == nil {
+		att.Size = info.Size()
+	}
 	m.Attachments = append(m.Attachments, att)
 	m.rebuildAttachmentUI()
+
+	if m.OnAttach != nil {
+		m.OnAttach(att)
+	}
 }
 
-// RemoveAttachment removes a file from the attachment list.
+// RemoveAttachment removes a file from the attachment list, cancelling its upload.
 func (m *MessageInput) RemoveAttachment(path string) {
 	for i, a := range m.Attachments {
 		if a.Path == path {
+			cancelUpload(a)
 			m.Attachments = append(m.Attachments[:i], m.Attachments[i+1:]...)
 			m.rebuildAttachmentUI()
+			m.submitIfUploaded()
 			return
 		}
 	}
 }
 
-// ClearAttachments clears all attachments.
+// ClearAttachments clears all attachments, cancelling unfinished uploads.
 func (m *MessageInput) ClearAttachments() {
-	m.Attachments = []Attachment{}
+	for _, a := range m.Attachments {
+		cancelUpload(a)
+	}
+	m.Attachments = []*Attachment{}
+	m.submitWhenUploaded = false
 	m.AttachmentContainer.Objects = nil
 	m.AttachmentContainer.Refresh()
 }
 
+// cancelUpload stops an attachment's upload, if it has one.
+func cancelUpload(att *Attachment) {
+	if att.Cancel != nil {
+		att.Cancel()
+		att.Cancel = nil
+	}
+}
+
+// SetAttachmentProgress shows how much of an attachment is uploaded, from 0 to 1.
+func (m *MessageInput) SetAttachmentProgress(att *Attachment, fraction float64) {
+	att.progress = fraction
+	if att.status != nil {
+		att.status.SetValue(fraction)
+	}
+}
+
+// SetAttachmentUploaded records an attachment's uploaded file ID.
+// A submit waiting for the uploads goes through once the last one finishes.
+func (m *MessageInput) SetAttachmentUploaded(att *Attachment, id string) {
+	att.ID = id
+	att.Cancel = nil
+	if att.status != nil {
+		att.status.Hide()
+	}
+	m.submitIfUploaded()
+}
+
+// SetAttachmentError marks an attachment whose upload failed, showing why on its card.
+// Sending stays blocked until the file is removed or a retry succeeds.
+func (m *MessageInput) SetAttachmentError(att *Attachment, err error) {
+	att.Err = err
+	att.Cancel = nil
+	m.submitWhenUploaded = false
+	m.showAttachmentFailure(att, err)
+}
+
+// showAttachmentFailure replaces an attachment's progress with a failure message and a Retry button.
+func (m *MessageInput) showAttachmentFailure(att *Attachment, err error) {
+	if att.status == nil {
+		return
+	}
+	att.status.Hide()
+	att.error.Importance = widget.DangerImportance
+	att.error.SetText(err.Error())
+	att.error.Show()
+	att.retry.Show()
+}
+
+// RetryAttachment uploads a failed attachment again.
+func (m *MessageInput) RetryAttachment(att *Attachment) {
+	att.Err = nil
+	att.progress = 0
+	m.rebuildAttachmentUI()
+
+	if m.OnAttach != nil {
+		m.OnAttach(att)
+	}
+}
+
+// SubmitWhenUploaded submits the message again once every attachment is uploaded.
+func (m *MessageInput) SubmitWhenUploaded() {
+	m.submitWhenUploaded = true
+}
+
+// submitIfUploaded submits a message waiting for its uploads once none are left.
+func (m *MessageInput) submitIfUploaded() {
+	if !m.submitWhenUploaded || m.OnSubmit == nil {
+		return
+	}
+	for _, a := range m.Attachments {
+		if a.Uploading() {
+			return
+		}
+	}
+	m.submitWhenUploaded = false
+	m.OnSubmit(m.expandMentions(m.Text))
+}
+
 // rebuildAttachmentUI rebuilds the attachment UI.
 func (m *MessageInput) rebuildAttachmentUI() {
 	m.AttachmentContainer.Objects = nil
 	for _, att := range m.Attachments {
-		size := 0
-		if info, err := os.Stat(att.Path); err == nil {
-			size = int(info.Size())
-		}
-
 		capturedPath := att.Path
 		onRemove := func() {
 			m.RemoveAttachment(capturedPath)
 		}
 
 		preview := m.createAttachmentPreview(att.Path)
-		bar := m.createAttachmentMetadataBar(att.Name, size, onRemove)
+		bar := m.createAttachmentMetadataBar(att.Name, int(att.Size), onRemove)
+
+		// Upload progress, replaced by the reason once the file cannot be sent
+		att.status = widget.NewProgressBar()
+		att.status.SetValue(att.progress)
+		att.status.Hidden = !att.Uploading()
+
+		att.error = widget.NewLabel("")
+		att.error.Wrapping = fyne.TextWrapWord
+		att.error.Hide()
+
+		capturedAtt := att
+		att.retry = widget.NewButton("Retry", func() {
+			m.RetryAttachment(capturedAtt)
+		})
+		att.retry.Importance = widget.LowImportance
+		att.retry.Hide()
+
+		if att.Err != nil {
+			m.showAttachmentFailure(att, att.Err)
+		}
 
-		main := container.NewBorder(nil, bar, nil, nil, preview)
+		failure := container.NewBorder(nil, nil, nil, att.retry, att.error)
+		main := container.NewBorder(nil, container.NewVBox(bar, att.status, failure), nil, nil, preview)
 
 		bg := canvas.NewRectangle(appTheme.Colors.ServerDefaultBg)
 		bg.CornerRadius = 8
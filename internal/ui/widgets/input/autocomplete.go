@@ -0,0 +1,359 @@
+package input
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+
+	"RGOClient/internal/cache"
+	"RGOClient/internal/context"
+	"RGOClient/internal/emoji"
+	appTheme "RGOClient/internal/ui/theme"
+	"RGOClient/internal/ui/widgets"
+	"RGOClient/internal/util"
+)
+
+// Autocomplete configuration.
+const (
+	maxSuggestions          = 8
+	minShortcodeQueryLength = 2 // ":sm" opens emoji suggestions, ":s" does not
+)
+
+// mentionPattern matches raw <@userID> mentions.
+var mentionPattern = regexp.MustCompile(`<@([0-9A-HJKMNP-TV-Z]{26})>`)
+
+// MentionCandidate is a user offered by mention autocomplete.
+type MentionCandidate struct {
+	ID        string
+	Name      string
+	AvatarURL string
+}
+
+// insertedMention is a readable "@name" put in the input by autocomplete or editing, expanded on submit.
+// Only these spans are expanded, so "@name" typed inside a word or address stays text.
+type insertedMention struct {
+	start int    // Rune offset of the '@' in Text
+	text  string // "@name" as shown
+	id    string
+}
+
+// suggestion is a row in the autocomplete list: either a user or an emoji.
+type suggestion struct {
+	mention *MentionCandidate
+	emoji   *emoji.Emoji
+}
+
+// updateSuggestions opens, filters or closes the autocomplete list based on the word under the cursor.
+// "@query" suggests users; ":query" suggests emoji by shortcode.
+func (m *MessageInput) updateSuggestions() {
+	runes := []rune(m.Text)
+	cursor := m.cursorOffset()
+
+	start := -1
+	for i := cursor - 1; i >= 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			break
+		}
+		if runes[i] == '@' || runes[i] == ':' {
+			if i == 0 || unicode.IsSpace(runes[i-1]) {
+				start = i
+			}
+			break
+		}
+	}
+
+	if start < 0 {
+		m.hideSuggestions()
+		return
+	}
+
+	query := string(runes[start+1 : cursor])
+	var suggestions []suggestion
+
+	switch runes[start] {
+	case '@':
+		if m.MentionSource != nil {
+			for _, candidate := range m.MentionSource(query) {
+				suggestions = append(suggestions, suggestion{mention: &candidate})
+			}
+		}
+	case ':':
+		if m.EmojiSource != nil && len([]rune(query)) >= minShortcodeQueryLength {
+			for _, e := range m.EmojiSource(query) {
+				suggestions = append(suggestions, suggestion{emoji: &e})
+			}
+		}
+	}
+
+	if len(suggestions) == 0 {
+		m.hideSuggestions()
+		return
+	}
+
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+
+	m.suggestionStart = start
+	m.suggestions = suggestions
+	m.suggestionIndex = 0
+	m.rebuildSuggestionUI()
+}
+
+// handleSuggestionKey handles navigation keys while the autocomplete list is open.
+// Returns true if the key was consumed.
+func (m *MessageInput) handleSuggestionKey(key fyne.KeyName) bool {
+	if len(m.suggestions) == 0 {
+		return false
+	}
+
+	switch key {
+	case fyne.KeyUp:
+		m.suggestionIndex = (m.suggestionIndex - 1 + len(m.suggestions)) % len(m.suggestions)
+	case fyne.KeyDown:
+		m.suggestionIndex = (m.suggestionIndex + 1) % len(m.suggestions)
+	case fyne.KeyTab, fyne.KeyReturn, fyne.KeyEnter:
+		m.acceptSuggestion(m.suggestionIndex)
+		return true
+	case fyne.KeyEscape:
+		m.hideSuggestions()
+		return true
+	default:
+		return false
+	}
+
+	m.rebuildSuggestionUI()
+	return true
+}
+
+// acceptSuggestion replaces the typed query with the picked suggestion.
+// Mentions are inserted as readable "@name" and remembered so they can be expanded on submit.
+func (m *MessageInput) acceptSuggestion(index int) {
+	if index < 0 || index >= len(m.suggestions) {
+		return
+	}
+
+	picked := m.suggestions[index]
+	var insert string
+	if picked.mention != nil {
+		insert = fmt.Sprintf("@%s", picked.mention.Name)
+	} else {
+		insert = picked.emoji.Text()
+		emoji.RecordUse(*picked.emoji)
+	}
+
+	runes := []rune(m.Text)
+	cursor := m.cursorOffset()
+	start := m.suggestionStart
+	inserted := []rune(insert + " ")
+
+	text := string(runes[:start]) + string(inserted) + string(runes[cursor:])
+	offset := start + len(inserted)
+
+	m.hideSuggestions()
+	m.SetText(text) // Shifts the mentions after the query, see trackMentions
+	if picked.mention != nil {
+		m.mentions = append(m.mentions, insertedMention{start: start, text: insert, id: picked.mention.ID})
+	}
+	m.setCursorOffset(offset)
+	m.Refresh()
+}
+
+// InsertText inserts text at the cursor, e.g. an emoji picked from the picker.
+func (m *MessageInput) InsertText(text string) {
+	runes := []rune(m.Text)
+	cursor := m.cursorOffset()
+
+	m.SetText(string(runes[:cursor]) + text + string(runes[cursor:]))
+	m.setCursorOffset(cursor + len([]rune(text)))
+	m.Refresh()
+}
+
+// hideSuggestions closes the autocomplete list.
+func (m *MessageInput) hideSuggestions() {
+	if len(m.suggestions) == 0 {
+		return
+	}
+
+	m.suggestions = nil
+	m.rebuildSuggestionUI()
+}
+
+// trackMentions moves inserted mentions along with an edit of the text. Mentions the edit touches are
+// forgotten, so a renamed or partly deleted "@name" is sent as typed.
+func (m *MessageInput) trackMentions(text string) {
+	before, after := []rune(m.mentionText), []rune(text)
+	m.mentionText = text
+	if len(m.mentions) == 0 {
+		return
+	}
+
+	// The edit replaced before[prefix:len(before)-suffix] with after[prefix:len(after)-suffix]
+	prefix := 0
+	for prefix < len(before) && prefix < len(after) && before[prefix] == after[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(before)-prefix && suffix < len(after)-prefix &&
+		before[len(before)-1-suffix] == after[len(after)-1-suffix] {
+		suffix++
+	}
+	editEnd := len(before) - suffix
+	shift := len(after) - len(before)
+
+	kept := m.mentions[:0]
+	for _, mention := range m.mentions {
+		switch {
+		case mention.start+len([]rune(mention.text)) <= prefix:
+			kept = append(kept, mention)
+		case mention.start >= editEnd:
+			mention.start += shift
+			kept = append(kept, mention)
+		}
+	}
+	m.mentions = kept
+}
+
+// expandMentions converts the readable mentions inserted by autocomplete or editing into <@userID>.
+func (m *MessageInput) expandMentions(text string) string {
+	runes := []rune(text)
+	mentions := slices.SortedFunc(slices.Values(m.mentions), func(a, b insertedMention) int {
+		return cmp.Compare(b.start, a.start) // Last first, so earlier offsets stay valid
+	})
+
+	for _, mention := range mentions {
+		end := mention.start + len([]rune(mention.text))
+		if end > len(runes) || string(runes[mention.start:end]) != mention.text {
+			continue
+		}
+		raw := []rune(fmt.Sprintf("<@%s>", mention.id))
+		runes = slices.Concat(runes[:mention.start], raw, runes[end:])
+	}
+	return string(runes)
+}
+
+// collapseMentions converts <@userID> into readable names for editing.
+// Returns the text and its mentions, to set once the text is in the input.
+func (m *MessageInput) collapseMentions(text string) (string, []insertedMention) {
+	session := context.Session()
+	if session == nil {
+		return text, nil
+	}
+
+	var b strings.Builder
+	var mentions []insertedMention
+	offset, last := 0, 0 // Rune offset of b's end; byte offset of the text not yet copied
+	for _, match := range mentionPattern.FindAllStringSubmatchIndex(text, -1) {
+		id := text[match[2]:match[3]]
+		user := session.State.User(id)
+		if user == nil {
+			continue
+		}
+
+		b.WriteString(text[last:match[0]])
+		offset += utf8.RuneCountInString(text[last:match[0]])
+		last = match[1]
+
+		display := fmt.Sprintf("@%s", user.Username)
+		mentions = append(mentions, insertedMention{start: offset, text: display, id: id})
+		b.WriteString(display)
+		offset += utf8.RuneCountInString(display)
+	}
+	b.WriteString(text[last:])
+	return b.String(), mentions
+}
+
+// rebuildSuggestionUI shows the suggestion list above the input.
+// A container is used instead of a widget.PopUp: overlays take keyboard focus away from the entry.
+func (m *MessageInput) rebuildSuggestionUI() {
+	m.SuggestionContainer.Objects = nil
+
+	if len(m.suggestions) > 0 {
+		list := container.NewVBox()
+		for i, s := range m.suggestions {
+			list.Add(m.buildSuggestionRow(i, s))
+		}
+
+		bg := canvas.NewRectangle(appTheme.Colors.SwiftActionBg)
+		bg.CornerRadius = 8
+		m.SuggestionContainer.Add(container.NewStack(bg, container.NewPadded(list)))
+	}
+
+	m.SuggestionContainer.Refresh()
+}
+
+// buildSuggestionRow creates a single suggestion; the selected row is highlighted.
+func (m *MessageInput) buildSuggestionRow(index int, s suggestion) fyne.CanvasObject {
+	iconSize := fyne.NewSize(20, 20)
+
+	var icon fyne.CanvasObject
+	var label string
+	if s.mention != nil {
+		label = s.mention.Name
+		placeholder := canvas.NewCircle(appTheme.Colors.ServerDefaultBg)
+		avatarContainer := container.NewGridWrap(iconSize, placeholder)
+
+		if avatarURL := s.mention.AvatarURL; avatarURL != "" {
+			avatarID := util.IDFromAttachmentURL(avatarURL)
+			if avatarID == "" {
+				avatarID = avatarURL
+			}
+			cache.GetImageCache().LoadImageToContainer(avatarID, avatarURL, iconSize, avatarContainer, true, nil)
+		}
+		icon = avatarContainer
+	} else {
+		label = ":" + s.emoji.Name + ":"
+		icon = container.NewGridWrap(iconSize, container.NewCenter(widgets.NewEmoji(s.emoji.Value, iconSize.Height)))
+	}
+
+	nameLabel := canvas.NewText(label, appTheme.Colors.TextPrimary)
+	nameLabel.TextSize = 14
+
+	row := widgets.HBoxNoSpacing(
+		widgets.HorizontalSpacer(6),
+		container.NewCenter(icon),
+		widgets.HorizontalSpacer(8),
+		container.NewCenter(nameLabel),
+	)
+
+	selected := canvas.NewRectangle(appTheme.Colors.SwiftActionBg)
+	selected.CornerRadius = 4
+	if index == m.suggestionIndex {
+		selected.FillColor = appTheme.Colors.SwiftActionHoverBg
+	}
+
+	tappable := widgets.NewTappableContainer(container.NewPadded(row), func() {
+		m.acceptSuggestion(index)
+	})
+	return container.NewStack(selected, tappable)
+}
+
+// cursorOffset returns the cursor position as a rune offset into Text.
+func (m *MessageInput) cursorOffset() int {
+	offset := 0
+	for row, line := range strings.Split(m.Text, "\n") {
+		if row == m.CursorRow {
+			return offset + min(m.CursorColumn, len([]rune(line)))
+		}
+		offset += len([]rune(line)) + 1
+	}
+	return len([]rune(m.Text))
+}
+
+// setCursorOffset moves the cursor to a rune offset into Text.
+func (m *MessageInput) setCursorOffset(offset int) {
+	runes := []rune(m.Text)
+	offset = min(offset, len(runes))
+
+	before := string(runes[:offset])
+	m.CursorRow = strings.Count(before, "\n")
+	m.CursorColumn = len([]rune(before[lastLineStart(before):]))
+}
@@ -0,0 +1,101 @@
+package input
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"github.com/sentinelb51/revoltgo"
+
+	appTheme "RGOClient/internal/ui/theme"
+	"RGOClient/internal/ui/widgets"
+)
+
+// StartEdit switches the input into edit mode for the given message.
+// The message content replaces the current text until the edit is submitted or cancelled.
+func (m *MessageInput) StartEdit(msg *revoltgo.Message) {
+	if msg == nil {
+		return
+	}
+
+	m.editingID = msg.ID
+	content, mentions := m.collapseMentions(msg.Content)
+	m.SetText(content)
+	m.mentions = mentions
+	m.CursorRow = m.currentLineCount() - 1
+	m.CursorColumn = len([]rune(content[lastLineStart(content):]))
+	m.rebuildEditUI()
+}
+
+// CancelEdit leaves edit mode and clears the input text.
+func (m *MessageInput) CancelEdit() {
+	if m.editingID == "" {
+		return
+	}
+
+	m.editingID = ""
+	m.SetText("")
+	m.rebuildEditUI()
+}
+
+// EditingID returns the ID of the message being edited, or an empty string.
+func (m *MessageInput) EditingID() string {
+	return m.editingID
+}
+
+// IsEditing returns true if the input is in edit mode.
+func (m *MessageInput) IsEditing() bool {
+	return m.editingID != ""
+}
+
+// rebuildEditUI shows or hides the "Editing message" banner.
+func (m *MessageInput) rebuildEditUI() {
+	m.EditContainer.Objects = nil
+	if m.editingID != "" {
+		m.EditContainer.Add(m.buildEditBanner())
+	}
+	m.EditContainer.Refresh()
+	m.Refresh()
+}
+
+// buildEditBanner creates the banner displayed above the input while editing.
+func (m *MessageInput) buildEditBanner() fyne.CanvasObject {
+	bg := canvas.NewRectangle(appTheme.Colors.SwiftActionBg)
+	bg.CornerRadius = 8
+
+	titleLabel := canvas.NewText("Editing message", appTheme.Colors.TextPrimary)
+	titleLabel.TextSize = 14
+	titleLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	hintLabel := canvas.NewText("escape to cancel • enter to save", appTheme.Colors.TimestampText)
+	hintLabel.TextSize = 12
+
+	textContainer := widgets.HBoxNoSpacing(
+		widgets.HorizontalSpacer(12),
+		container.NewCenter(titleLabel),
+		widgets.HorizontalSpacer(10),
+		container.NewCenter(hintLabel),
+	)
+
+	closeBtn := widgets.NewCloseButton(func() {
+		m.CancelEdit()
+	})
+
+	layoutContent := container.NewBorder(nil, nil, textContainer, closeBtn)
+
+	layoutContentPadded := container.NewBorder(
+		widgets.VerticalSpacer(2), widgets.VerticalSpacer(2),
+		widgets.HorizontalSpacer(4), widgets.HorizontalSpacer(4),
+		layoutContent,
+	)
+	return container.NewStack(bg, layoutContentPadded)
+}
+
+// lastLineStart returns the byte offset where the last line of text begins.
+func lastLineStart(text string) int {
+	for i := len(text) - 1; i >= 0; i-- {
+		if text[i] == '\n' {
+			return i + 1
+		}
+	}
+	return 0
+}
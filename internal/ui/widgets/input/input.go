@@ -14,6 +14,7 @@ import (
 	"fyne.io/fyne/v2/widget"
 	"golang.design/x/clipboard"
 
+	"RGOClient/internal/emoji"
 	"RGOClient/internal/interfaces"
 )
 
@@ -42,11 +43,35 @@ type MessageInput struct {
 	OnSubmit            func(string)
 	shiftPressed        bool
 	Actions             interfaces.MessageActions // For resolving messages
-	Attachments         []Attachment
+	Attachments         []*Attachment
 	AttachmentContainer *fyne.Container
+	OnAttach            func(att *Attachment) // Starts uploading a newly attached file
+	submitWhenUploaded  bool                  // Submit once the remaining uploads finish
 
 	Replies        []Reply
 	ReplyContainer *fyne.Container
+
+	// Edit mode: OnEdit is called on Enter instead of OnSubmit
+	OnEdit        func(messageID, text string)
+	OnEditLast    func() // Called on Up in an empty input
+	EditContainer *fyne.Container
+	editingID     string
+
+	// Typing notifications: OnTyping(true) on begin, OnTyping(false) on end
+	OnTyping     func(typing bool)
+	typing       bool
+	typingSentAt time.Time
+	typingTimer  *time.Timer
+
+	// Autocomplete: sources return ranked matches for a query (without the '@' or ':' trigger)
+	MentionSource       func(query string) []MentionCandidate
+	EmojiSource         func(query string) []emoji.Emoji
+	SuggestionContainer *fyne.Container
+	mentions            []insertedMention // Expanded on submit
+	mentionText         string            // Text the mention offsets refer to
+	suggestions         []suggestion
+	suggestionIndex     int
+	suggestionStart     int // Rune offset of the trigger character being completed
 }
 
 // NewMessageInput creates a new MessageInput widget.
@@ -57,7 +82,10 @@ func NewMessageInput() *MessageInput {
 	m.Wrapping = fyne.TextWrapWord
 	m.AttachmentContainer = container.NewHBox()
 	m.ReplyContainer = container.NewVBox()
+	m.EditContainer = container.NewVBox()
+	m.SuggestionContainer = container.NewVBox()
 	m.Replies = []Reply{}
+	m.OnChanged = m.onTextChanged
 	return m
 }
 
@@ -113,15 +141,33 @@ func (m *MessageInput) KeyUp(key *fyne.KeyEvent) {
 
 // TypedKey handles key events for the MessageInput.
 func (m *MessageInput) TypedKey(key *fyne.KeyEvent) {
+	if m.handleSuggestionKey(key.Name) {
+		return
+	}
+
 	// Force size recalculation for deletion keys
 	if key.Name == fyne.KeyBackspace || key.Name == fyne.KeyDelete {
 		m.Entry.TypedKey(key)
+		m.updateSuggestions()
 		m.Refresh()
 		return
 	}
 
+	if key.Name == fyne.KeyEscape && m.IsEditing() {
+		m.CancelEdit()
+		return
+	}
+
+	if key.Name == fyne.KeyUp && m.Text == "" && !m.IsEditing() && m.OnEditLast != nil {
+		m.OnEditLast()
+		return
+	}
+
 	if key.Name != fyne.KeyReturn && key.Name != fyne.KeyEnter {
 		m.Entry.TypedKey(key)
+		if key.Name == fyne.KeyLeft || key.Name == fyne.KeyRight || key.Name == fyne.KeyHome || key.Name == fyne.KeyEnd {
+			m.updateSuggestions()
+		}
 		return
 	}
 
@@ -131,8 +177,13 @@ func (m *MessageInput) TypedKey(key *fyne.KeyEvent) {
 		return
 	}
 
-	if m.OnSubmit != nil {
-		m.OnSubmit(m.Text)
+	text := m.expandMentions(m.Text)
+	if m.IsEditing() {
+		if m.OnEdit != nil {
+			m.OnEdit(m.editingID, text)
+		}
+	} else if m.OnSubmit != nil {
+		m.OnSubmit(text)
 	}
 	m.Refresh()
 }
@@ -140,6 +191,7 @@ func (m *MessageInput) TypedKey(key *fyne.KeyEvent) {
 // TypedRune ensures size recalculation after edits.
 func (m *MessageInput) TypedRune(r rune) {
 	m.Entry.TypedRune(r)
+	m.updateSuggestions()
 	m.Refresh()
 }
 
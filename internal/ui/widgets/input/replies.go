@@ -54,6 +54,21 @@ func (m *MessageInput) RemoveReply(messageID string) {
 	}
 }
 
+// HasReply returns true if the message is being replied to.
+func (m *MessageInput) HasReply(messageID string) bool {
+	for _, r := range m.Replies {
+		if r.ID == messageID {
+			return true
+		}
+	}
+	return false
+}
+
+// RefreshReplies rebuilds the reply cards, e.g. once a referenced message was resolved.
+func (m *MessageInput) RefreshReplies() {
+	m.rebuildReplyUI()
+}
+
 // ClearReplies clears all replies.
 func (m *MessageInput) ClearReplies() {
 	m.Replies = make([]Reply, 0, maxReplyCount)
@@ -87,6 +102,9 @@ func (m *MessageInput) buildReplyCard(r *Reply) fyne.CanvasObject {
 				authorName = util.DisplayName(msg)
 				avatarURL = util.DisplayAvatarURL(msg)
 				content = msg.Content
+			} else if m.Actions.IsMessageDeleted(r.ID) {
+				authorName = "Unknown"
+				content = "[Original message was deleted]"
 			} else {
 				authorName = "Unknown"
 				content = "[Message not found]"
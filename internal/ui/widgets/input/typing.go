@@ -0,0 +1,67 @@
+package input
+
+import (
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// Typing notification timings.
+const (
+	typingResendInterval = 3 * time.Second // Minimum gap between begin typing events
+	typingIdleTimeout    = 5 * time.Second // Send end typing after this long without changes
+)
+
+// onTextChanged notifies OnTyping when the user types, throttled to one begin event per interval.
+func (m *MessageInput) onTextChanged(text string) {
+	m.trackMentions(text)
+	if text == "" {
+		m.hideSuggestions()
+	}
+
+	// Edits and programmatic changes (reply/edit setup) are not typing
+	if m.IsEditing() {
+		return
+	}
+
+	if strings.TrimSpace(text) == "" {
+		m.StopTyping()
+		return
+	}
+
+	if m.typingTimer != nil {
+		m.typingTimer.Stop()
+	}
+	m.typingTimer = time.AfterFunc(typingIdleTimeout, func() {
+		fyne.CurrentApp().Driver().DoFromGoroutine(m.StopTyping, false)
+	})
+
+	if m.typing && time.Since(m.typingSentAt) < typingResendInterval {
+		return
+	}
+
+	m.typing = true
+	m.typingSentAt = time.Now()
+	if m.OnTyping != nil {
+		m.OnTyping(true)
+	}
+}
+
+// StopTyping sends an end typing notification if one is pending.
+// Call before switching channels so the previous channel is notified.
+func (m *MessageInput) StopTyping() {
+	if m.typingTimer != nil {
+		m.typingTimer.Stop()
+		m.typingTimer = nil
+	}
+
+	if !m.typing {
+		return
+	}
+
+	m.typing = false
+	if m.OnTyping != nil {
+		m.OnTyping(false)
+	}
+}
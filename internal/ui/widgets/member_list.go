@@ -0,0 +1,207 @@
+package widgets
+
+import (
+	"image"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"github.com/sentinelb51/revoltgo"
+
+	"RGOClient/internal/cache"
+	"RGOClient/internal/ui/theme"
+	"RGOClient/internal/util"
+)
+
+// Compile-time interface assertions.
+var (
+	_ fyne.Widget = (*MemberList)(nil)
+	_ fyne.Widget = (*memberListItem)(nil)
+)
+
+// MemberRow is one row of the member list: either a group header or a member.
+type MemberRow struct {
+	Header     string // Set for group headers ("Moderators — 3"); other fields are empty
+	UserID     string
+	Name       string
+	NameColour color.Color // Colour of the highest coloured role, or nil
+	AvatarURL  string
+	Presence   revoltgo.UserStatusPresence
+	Online     bool
+}
+
+// MemberList is the server member sidebar: a filter box above a virtualised list.
+// Only visible rows are rendered, so large servers stay responsive.
+type MemberList struct {
+	widget.BaseWidget
+	OnMemberTapped  func(userID string)
+	OnFilterChanged func(query string)
+
+	filter *widget.Entry
+	list   *widget.List
+	rows   []MemberRow
+}
+
+// NewMemberList creates an empty member list.
+func NewMemberList(onMemberTapped func(userID string), onFilterChanged func(query string)) *MemberList {
+	w := &MemberList{
+		OnMemberTapped:  onMemberTapped,
+		OnFilterChanged: onFilterChanged,
+	}
+
+	w.filter = widget.NewEntry()
+	w.filter.SetPlaceHolder("Filter members")
+	w.filter.OnChanged = func(query string) {
+		if w.OnFilterChanged != nil {
+			w.OnFilterChanged(query)
+		}
+	}
+
+	w.list = widget.NewList(
+		func() int { return len(w.rows) },
+		func() fyne.CanvasObject { return newMemberListItem() },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			if id < len(w.rows) {
+				o.(*memberListItem).set(w.rows[id])
+			}
+		},
+	)
+	w.list.HideSeparators = true
+	w.list.OnSelected = func(id widget.ListItemID) {
+		w.list.Unselect(id)
+		if id < len(w.rows) && w.rows[id].Header == "" && w.OnMemberTapped != nil {
+			w.OnMemberTapped(w.rows[id].UserID)
+		}
+	}
+
+	w.ExtendBaseWidget(w)
+	return w
+}
+
+// CreateRenderer returns the widget renderer.
+func (w *MemberList) CreateRenderer() fyne.WidgetRenderer {
+	bg := canvas.NewRectangle(theme.Colors.ChannelListBackground)
+	bg.SetMinSize(fyne.NewSize(theme.Sizes.MemberListWidth, 0))
+
+	content := container.NewBorder(container.NewPadded(w.filter), nil, nil, nil, w.list)
+	return widget.NewSimpleRenderer(container.NewStack(bg, content))
+}
+
+// SetRows replaces the displayed rows.
+func (w *MemberList) SetRows(rows []MemberRow) {
+	w.rows = rows
+	for i, row := range rows {
+		if row.Header != "" {
+			w.list.SetItemHeight(i, theme.Sizes.MemberHeaderHeight)
+		} else {
+			w.list.SetItemHeight(i, theme.Sizes.MemberItemHeight)
+		}
+	}
+	w.list.Refresh()
+}
+
+// Filter returns the current filter text.
+func (w *MemberList) Filter() string {
+	return w.filter.Text
+}
+
+// memberListItem renders a MemberRow. Items are recycled by the list, so image loads
+// check that the item still shows the same user before applying.
+type memberListItem struct {
+	widget.BaseWidget
+	userID string
+
+	header *canvas.Text
+	avatar *canvas.Image
+	dot    *canvas.Circle
+	name   *canvas.Text
+	member *fyne.Container
+}
+
+func newMemberListItem() *memberListItem {
+	avatarSize := fyne.NewSquareSize(theme.Sizes.MemberAvatarSize)
+	dotSize := fyne.NewSquareSize(theme.Sizes.PresenceDotSize)
+
+	w := &memberListItem{
+		header: canvas.NewText("", theme.Colors.CategoryText),
+		avatar: canvas.NewImageFromImage(nil),
+		dot:    canvas.NewCircle(theme.Colors.PresenceOffline),
+		name:   canvas.NewText("", theme.Colors.TextPrimary),
+	}
+	w.header.TextSize = 11
+	w.header.TextStyle = fyne.TextStyle{Bold: true}
+	w.name.TextSize = 14
+	w.avatar.FillMode = canvas.ImageFillContain
+	w.avatar.SetMinSize(avatarSize)
+
+	// Presence dot over the bottom-right corner of the avatar
+	placeholder := canvas.NewCircle(theme.Colors.AvatarPlaceholder)
+	dotCorner := container.NewVBox(layout.NewSpacer(), container.NewHBox(layout.NewSpacer(), container.NewGridWrap(dotSize, w.dot)))
+	avatar := container.NewGridWrap(avatarSize, container.NewStack(placeholder, w.avatar, dotCorner))
+
+	w.member = HBoxNoSpacing(
+		HorizontalSpacer(8),
+		container.NewCenter(avatar),
+		HorizontalSpacer(10),
+		container.NewCenter(w.name),
+	)
+
+	w.ExtendBaseWidget(w)
+	return w
+}
+
+// CreateRenderer returns the widget renderer.
+func (w *memberListItem) CreateRenderer() fyne.WidgetRenderer {
+	header := container.NewBorder(nil, nil, HorizontalSpacer(8), nil, container.NewVBox(layout.NewSpacer(), w.header))
+	return widget.NewSimpleRenderer(container.NewStack(header, w.member))
+}
+
+// set shows a row in this item.
+func (w *memberListItem) set(row MemberRow) {
+	if row.Header != "" {
+		w.userID = ""
+		w.header.Text = row.Header
+		w.header.Show()
+		w.member.Hide()
+		w.header.Refresh()
+		return
+	}
+
+	w.header.Hide()
+	w.member.Show()
+
+	w.name.Text = row.Name
+	w.name.Color = theme.Colors.TextPrimary
+	if row.NameColour != nil {
+		w.name.Color = row.NameColour
+	}
+	w.name.Refresh()
+
+	w.dot.FillColor, _ = presenceStyle(row.Presence, row.Online)
+	w.dot.Refresh()
+
+	if w.userID != row.UserID {
+		w.userID = row.UserID
+		w.avatar.Image = nil
+		w.avatar.Refresh()
+
+		if url := row.AvatarURL; url != "" {
+			avatarID := util.IDFromAttachmentURL(url)
+			if avatarID == "" {
+				avatarID = url
+			}
+
+			userID := row.UserID
+			cache.GetImageCache().LoadFromURLAsync(avatarID, url, true, func(img image.Image) {
+				if w.userID != userID {
+					return // Recycled for another member meanwhile
+				}
+				w.avatar.Image = img
+				w.avatar.Refresh()
+			})
+		}
+	}
+}
@@ -3,10 +3,12 @@ package widgets
 import (
 	"RGOClient/internal/cache"
 	"RGOClient/internal/context"
+	"RGOClient/internal/emoji"
 	"RGOClient/internal/interfaces"
 	"RGOClient/internal/ui/theme"
 	"RGOClient/internal/util"
 	"image/color"
+	"slices"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -18,7 +20,11 @@ import (
 )
 
 const (
-	maxReplyPreviewLength = 80
+	maxReplyPreviewLength    = 80
+	messageHighlightDuration = 1500 * time.Millisecond
+
+	unknownReplyText = "Unknown message reference"
+	deletedReplyText = "Original message was deleted"
 )
 
 // Compile-time interface assertions.
@@ -30,8 +36,10 @@ var _ desktop.Hoverable = (*swiftActionButton)(nil)
 // MessageWidget displays a chat message with hover effects.
 type MessageWidget struct {
 	widget.BaseWidget
+	Message    *revoltgo.Message
 	content    fyne.CanvasObject
 	background *canvas.Rectangle
+	highlight  *canvas.Rectangle // Briefly shown when jumped to
 	actionsRow *fyne.Container
 
 	// Hover state management to prevent flicker
@@ -53,7 +61,9 @@ func NewMessageWidget(
 	}
 
 	w := &MessageWidget{
+		Message:    message,
 		background: canvas.NewRectangle(color.Transparent),
+		highlight:  canvas.NewRectangle(color.Transparent),
 	}
 
 	var (
@@ -87,19 +97,53 @@ func NewMessageWidget(
 		}
 	}, onActionHover)
 
-	editBtn := newSwiftActionButton("assets/edit.svg", func() {
+	deleteBtn := newSwiftActionButton("assets/trash.svg", func() {
 		if actions != nil {
-			actions.OnEdit(message.ID)
+			actions.OnDelete(message.ID)
 		}
 	}, onActionHover)
 
-	deleteBtn := newSwiftActionButton("assets/trash.svg", func() {
-		if actions != nil {
-			actions.OnDelete(message.ID)
+	var reactBtn *swiftActionButton
+	reactBtn = newSwiftActionButton("assets/emoji.svg", func() {
+		if actions == nil {
+			return
 		}
+
+		// The widget's message may be outdated; reactions are read from the cached copy
+		current := func() *revoltgo.Message {
+			if cached := actions.CachedMessage(message.Channel, message.ID); cached != nil {
+				return cached
+			}
+			return message
+		}
+
+		shown := current()
+		allowed := func(e emoji.Emoji) bool {
+			return util.CanReact(shown, e.Value)
+		}
+		ShowEmojiPicker(reactBtn, actions.CustomEmoji(), allowed, func(e emoji.Emoji) {
+			// OnReact toggles; picking an emoji we already reacted with should not remove it
+			latest := current()
+			if self := session.State.Self(); self != nil && slices.Contains(latest.Reactions[e.Value], self.ID) {
+				return
+			}
+			actions.OnReact(latest, e.Value)
+		})
 	}, onActionHover)
 
-	actionsContainer := HBoxNoSpacing(replyBtn, editBtn, deleteBtn)
+	actionsContainer := HBoxNoSpacing(reactBtn, replyBtn)
+
+	// Only our own messages can be edited
+	if self := session.State.Self(); self != nil && message.Author == self.ID && message.System == nil {
+		editBtn := newSwiftActionButton("assets/edit.svg", func() {
+			if actions != nil {
+				actions.OnEdit(message.ID)
+			}
+		}, onActionHover)
+		actionsContainer.Add(editBtn)
+	}
+
+	actionsContainer.Add(deleteBtn)
 
 	// Rounded background for the action group
 	actionsBg := canvas.NewRectangle(theme.Colors.SwiftActionBg)
@@ -111,8 +155,11 @@ func NewMessageWidget(
 	actionsGroup.Hide()
 	w.actionsRow = actionsGroup
 
+	// Images load in the shown messages' scope, prioritised while this message is on screen
+	images := imageOwner(actions, message.ID)
+
 	// Build avatar column
-	avatar := NewClickableAvatar(displayAvatarID, displayAvatarURL, message.Author, func() {
+	avatar := NewClickableAvatar(images, displayAvatarID, displayAvatarURL, message.Author, func() {
 		if actions != nil {
 			actions.OnAvatarTapped(message.Author)
 		}
@@ -150,7 +197,7 @@ func NewMessageWidget(
 	if len(message.Replies) > 0 {
 		repliesContainer := container.NewVBox()
 		for _, replyID := range message.Replies {
-			repliesContainer.Add(buildReplyPreview(replyID, message.Channel, actions))
+			repliesContainer.Add(buildReplyPreview(replyID, message.Channel, images, actions))
 			repliesContainer.Add(NewVSpacer(-15))
 		}
 		// No extra padding here to keep it close to the message
@@ -164,9 +211,52 @@ func NewMessageWidget(
 	return w
 }
 
+// NewMessagePreview creates a static, non-interactive rendering of a message.
+// Used in dialogs (e.g. delete confirmation) where swift actions make no sense.
+func NewMessagePreview(message *revoltgo.Message) fyne.CanvasObject {
+	var (
+		displayName      = util.DisplayName(message)
+		displayAvatarURL = util.DisplayAvatarURL(message)
+		displayAvatarID  = util.IDFromAttachmentURL(displayAvatarURL)
+	)
+
+	content := message.Content
+	if message.System != nil {
+		content = util.FormatSystemMessage(message.System)
+	}
+
+	var timestamp string
+	if t, err := util.Timestamp(message.ID); err == nil {
+		timestamp = util.NiceTime(t)
+	}
+
+	avatar := NewClickableAvatar(cache.ImageOwner{}, displayAvatarID, displayAvatarURL, message.Author, nil)
+	avatarColumn := container.New(&VerticalCenterFixedWidthLayout{Width: theme.Sizes.MessageAvatarColumnWidth}, avatar)
+	contentWidget := buildMessageContent(message, displayName, timestamp, content, nil)
+
+	bg := canvas.NewRectangle(theme.Colors.MessageAreaBackground)
+	bg.CornerRadius = 8
+
+	main := container.NewBorder(nil, nil, avatarColumn, nil, contentWidget)
+	return container.NewStack(bg, container.NewPadded(main))
+}
+
 // CreateRenderer returns the widget renderer.
 func (w *MessageWidget) CreateRenderer() fyne.WidgetRenderer {
-	return widget.NewSimpleRenderer(container.NewStack(w.background, w.content))
+	return widget.NewSimpleRenderer(container.NewStack(w.background, w.highlight, w.content))
+}
+
+// Highlight briefly tints the message, e.g. after jumping to it from a reply.
+func (w *MessageWidget) Highlight() {
+	w.highlight.FillColor = theme.Colors.MessageHighlightBackground
+	w.highlight.Refresh()
+
+	time.AfterFunc(messageHighlightDuration, func() {
+		fyne.CurrentApp().Driver().DoFromGoroutine(func() {
+			w.highlight.FillColor = color.Transparent
+			w.highlight.Refresh()
+		}, false)
+	})
 }
 
 // updateHoverState updates visibility based on hover flags with debounce.
@@ -218,7 +308,7 @@ func (w *MessageWidget) MouseOut() {
 	w.updateHoverState()
 }
 
-func buildReplyPreview(replyID string, channelID string, actions interfaces.MessageActions) fyne.CanvasObject {
+func buildReplyPreview(replyID string, channelID string, images cache.ImageOwner, actions interfaces.MessageActions) fyne.CanvasObject {
 	var authorName, content, avatarURL string
 
 	session := context.Session()
@@ -228,11 +318,13 @@ func buildReplyPreview(replyID string, channelID string, actions interfaces.Mess
 			authorName = util.DisplayName(msg)
 			content = msg.Content
 			avatarURL = util.DisplayAvatarURL(msg)
+		} else if actions.IsMessageDeleted(replyID) {
+			content = deletedReplyText
 		} else {
-			content = "Unknown message reference"
+			content = unknownReplyText
 		}
 	} else {
-		content = "Unknown message reference"
+		content = unknownReplyText
 	}
 
 	if len(content) > maxReplyPreviewLength {
@@ -249,7 +341,7 @@ func buildReplyPreview(replyID string, channelID string, actions interfaces.Mess
 		if avatarID == "" {
 			avatarID = avatarURL
 		}
-		cache.GetImageCache().LoadImageToContainer(avatarID, avatarURL, avatarSize, avatarContainer, true, nil)
+		images.LoadImageToContainer(avatarID, avatarURL, avatarSize, avatarContainer, true, nil)
 	}
 
 	// 3. Text
@@ -272,7 +364,9 @@ func buildReplyPreview(replyID string, channelID string, actions interfaces.Mess
 	paddedRow := container.NewBorder(VerticalSpacer(3), VerticalSpacer(3), HorizontalSpacer(3), HorizontalSpacer(3), replyRow)
 
 	tappableReply := NewTappableContainer(paddedRow, func() {
-		// TODO: Navigate to message
+		if actions != nil {
+			actions.OnReplyTapped(channelID, replyID)
+		}
 	})
 
 	return container.NewHBox(HorizontalSpacer(40), tappableReply)
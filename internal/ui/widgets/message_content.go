@@ -10,11 +10,13 @@ import (
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/layout"
+	fyneTheme "fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 	"github.com/sentinelb51/revoltgo"
 
 	"RGOClient/internal/cache"
 	"RGOClient/internal/interfaces"
+	"RGOClient/internal/ui/markdown"
 	"RGOClient/internal/ui/theme"
 	"RGOClient/internal/util"
 )
@@ -25,18 +27,43 @@ func buildMessageContent(
 	username, timestamp, messageText string,
 	actions interfaces.MessageActions,
 ) fyne.CanvasObject {
-	header := buildMessageHeader(username, messageText, timestamp)
+	// System and webhook messages have no user profile to open
+	authorID := message.Author
+	if message.System != nil || message.Webhook != nil {
+		authorID = ""
+	}
+
+	header := buildMessageHeader(authorID, username, messageText, timestamp, message.Edited != nil, actions)
+	reactions := buildReactionsRow(message, actions)
 
-	if message.Attachments == nil || len(message.Attachments) == 0 {
+	if len(message.Attachments) == 0 && reactions == nil {
 		return header
 	}
 
-	attachmentsContainer := buildAttachmentsContainer(message.Attachments, actions)
-	return container.NewVBox(header, attachmentsContainer)
+	content := container.NewVBox(header)
+	if len(message.Attachments) > 0 {
+		content.Add(buildAttachmentsContainer(message.Attachments, imageOwner(actions, message.ID), actions))
+	}
+	if reactions != nil {
+		content.Add(reactions)
+	}
+	return content
 }
 
-func buildMessageHeader(username, messageText, timestamp string) fyne.CanvasObject {
-	text := createFormattedMessage(username, messageText)
+// imageOwner returns the owner of a message's image loads: the message within the scope of the shown
+// messages, or unscoped without actions.
+func imageOwner(actions interfaces.MessageActions, messageID string) cache.ImageOwner {
+	if actions == nil {
+		return cache.ImageOwner{}
+	}
+	return actions.ImageScope().Owner(messageID)
+}
+
+func buildMessageHeader(authorID, username, messageText, timestamp string, edited bool, actions interfaces.MessageActions) fyne.CanvasObject {
+	text := createFormattedMessage(authorID, username, messageText, actions)
+	if edited {
+		appendEditedMarker(text)
+	}
 
 	tsText := canvas.NewText(timestamp, theme.Colors.TimestampText)
 	tsText.TextSize = theme.Sizes.MessageTimestampSize
@@ -50,7 +77,7 @@ func buildMessageHeader(username, messageText, timestamp string) fyne.CanvasObje
 	return container.NewStack(text, timestampOverlay)
 }
 
-func buildAttachmentsContainer(attachments []*revoltgo.Attachment, actions interfaces.MessageActions) *fyne.Container {
+func buildAttachmentsContainer(attachments []*revoltgo.Attachment, images cache.ImageOwner, actions interfaces.MessageActions) *fyne.Container {
 	containerBox := container.NewVBox()
 	first := true
 
@@ -59,7 +86,7 @@ func buildAttachmentsContainer(attachments []*revoltgo.Attachment, actions inter
 			containerBox.Add(VerticalSpacer(theme.Sizes.MessageAttachmentSpacing))
 		}
 
-		attachmentWidget := buildSingleAttachment(attachment, actions)
+		attachmentWidget := buildSingleAttachment(attachment, images, actions)
 		padded := container.NewBorder(nil, nil, HorizontalSpacer(theme.Sizes.MessageTextLeftPadding), nil, container.NewHBox(attachmentWidget))
 		containerBox.Add(padded)
 		first = false
@@ -67,7 +94,7 @@ func buildAttachmentsContainer(attachments []*revoltgo.Attachment, actions inter
 	return containerBox
 }
 
-func buildSingleAttachment(attachment *revoltgo.Attachment, actions interfaces.MessageActions) fyne.CanvasObject {
+func buildSingleAttachment(attachment *revoltgo.Attachment, images cache.ImageOwner, actions interfaces.MessageActions) fyne.CanvasObject {
 	isImage := attachment.Metadata.Type == revoltgo.AttachmentMetadataTypeImage
 	isText := util.Filetype(attachment.Filename) == util.FileTypeText
 
@@ -75,7 +102,7 @@ func buildSingleAttachment(attachment *revoltgo.Attachment, actions interfaces.M
 	var contentStack *fyne.Container
 
 	if isImage {
-		contentStack = buildImageAttachment(attachment, barStack)
+		contentStack = buildImageAttachment(attachment, images, barStack)
 	} else if isText {
 		contentStack = buildTextAttachment(attachment, barStack)
 	} else {
@@ -114,7 +141,7 @@ func createAttachmentBar(attachment *revoltgo.Attachment) fyne.CanvasObject {
 }
 
 // todo: if we're calling this, attachment probably has URL and is not nil?
-func buildImageAttachment(attachment *revoltgo.Attachment, barStack fyne.CanvasObject) *fyne.Container {
+func buildImageAttachment(attachment *revoltgo.Attachment, images cache.ImageOwner, barStack fyne.CanvasObject) *fyne.Container {
 	size := calculateImageSize(attachment.Metadata.Width, attachment.Metadata.Height)
 	placeholder := canvas.NewRectangle(theme.Colors.ServerDefaultBg)
 	placeholder.SetMinSize(size)
@@ -122,7 +149,7 @@ func buildImageAttachment(attachment *revoltgo.Attachment, barStack fyne.CanvasO
 
 	attachmentURL := attachment.URL("")
 	if attachmentURL != "" && attachment.ID != "" {
-		cache.GetImageCache().LoadImageToContainer(attachment.ID, attachmentURL, size, imgContainer, false, nil)
+		images.LoadImageToContainer(attachment.ID, attachmentURL, size, imgContainer, false, nil)
 	}
 
 	return container.NewBorder(nil, barStack, nil, nil, imgContainer)
@@ -190,13 +217,57 @@ func buildGenericAttachment(_ *revoltgo.Attachment, barStack fyne.CanvasObject)
 }
 
 // createFormattedMessage creates a RichText widget with bold username and formatted content.
-func createFormattedMessage(username, message string) *widget.RichText {
-	content := fmt.Sprintf("**%s**\n\n%s", username, message)
-	rt := widget.NewRichTextFromMarkdown(content)
+// The username, mentions and channel links are routed to actions when tapped.
+func createFormattedMessage(authorID, username, message string, actions interfaces.MessageActions) *widget.RichText {
+	var onUser, onChannel func(string)
+	if actions != nil {
+		onUser, onChannel = actions.OnAvatarTapped, actions.OnChannelTapped
+	}
+
+	author := &authorSegment{Name: username}
+	if onUser != nil && authorID != "" {
+		author.OnTapped = func() { onUser(authorID) }
+	}
+
+	segments := []widget.RichTextSegment{
+		author,
+		&widget.TextSegment{Style: widget.RichTextStyleParagraph},
+	}
+	segments = append(segments, markdown.NewRenderer(onUser, onChannel).Render(message)...)
+
+	rt := widget.NewRichText(segments...)
 	rt.Wrapping = fyne.TextWrapWord
 	return rt
 }
 
+// appendEditedMarker adds a dimmed "(edited)" suffix to the last paragraph of the message.
+func appendEditedMarker(rt *widget.RichText) {
+	marker := &widget.TextSegment{
+		Text: " (edited)",
+		Style: widget.RichTextStyle{
+			ColorName: fyneTheme.ColorNamePlaceHolder,
+			Inline:    true,
+			SizeName:  fyneTheme.SizeNameCaptionText,
+		},
+	}
+
+	// Insert before the trailing paragraph break so the marker stays on the last line of text.
+	// Blocks such as code and headings hold their own text, so the marker goes on a line below them.
+	segments := rt.Segments
+	if n := len(segments); n > 0 && isParagraphBreak(segments[n-1]) {
+		rt.Segments = append(segments[:n-1:n-1], marker, segments[n-1])
+	} else {
+		rt.Segments = append(segments, marker)
+	}
+	rt.Refresh()
+}
+
+// isParagraphBreak reports whether a segment only ends the line before it.
+func isParagraphBreak(segment widget.RichTextSegment) bool {
+	text, ok := segment.(*widget.TextSegment)
+	return ok && text.Text == "" && !text.Inline()
+}
+
 // calculateImageSize calculates display size respecting max dimensions.
 func calculateImageSize(width, height int) fyne.Size {
 	maxW := theme.Sizes.MessageImageMaxWidth
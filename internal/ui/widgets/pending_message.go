@@ -0,0 +1,138 @@
+package widgets
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"github.com/sentinelb51/revoltgo"
+
+	"RGOClient/internal/cache"
+	"RGOClient/internal/ui/theme"
+	"RGOClient/internal/util"
+)
+
+// Compile-time interface assertion.
+var _ fyne.Widget = (*PendingMessage)(nil)
+
+// PendingMessageInfo describes a message waiting in the outbox.
+type PendingMessageInfo struct {
+	Message     *revoltgo.Message // Local copy: the nonce as ID, our user as author
+	Attachments []string          // File names
+	Failed      bool              // Gave up retrying
+	Error       string            // Last send error, if any
+	Sent        bool              // Delivered; waiting for the gateway to echo it
+}
+
+// PendingMessage shows an unsent message below the channel's messages: dimmed while sending,
+// with upload progress, or with Retry and Discard actions once sending failed.
+type PendingMessage struct {
+	widget.BaseWidget
+	Info PendingMessageInfo
+
+	OnRetry   func(nonce string)
+	OnDiscard func(nonce string)
+
+	content  fyne.CanvasObject
+	status   *canvas.Text
+	progress *widget.ProgressBar
+}
+
+// NewPendingMessage creates a widget for an outbox entry.
+func NewPendingMessage(info PendingMessageInfo, onRetry, onDiscard func(nonce string)) *PendingMessage {
+	w := &PendingMessage{Info: info, OnRetry: onRetry, OnDiscard: onDiscard}
+	w.content = w.build()
+	w.ExtendBaseWidget(w)
+	return w
+}
+
+// build lays the message out like a MessageWidget, dimmed, followed by its send status.
+func (w *PendingMessage) build() fyne.CanvasObject {
+	message := w.Info.Message
+
+	var timestamp string
+	if t, err := util.Timestamp(message.ID); err == nil {
+		timestamp = util.NiceTime(t)
+	}
+
+	avatarURL := util.DisplayAvatarURL(message)
+	avatar := NewClickableAvatar(cache.ImageOwner{}, util.IDFromAttachmentURL(avatarURL), avatarURL, message.Author, nil)
+	avatarColumn := container.New(&VerticalCenterFixedWidthLayout{Width: theme.Sizes.MessageAvatarColumnWidth}, avatar)
+	contentWidget := buildMessageContent(message, util.DisplayName(message), timestamp, message.Content, nil)
+
+	padding := theme.Sizes.MessageHorizontalPadding
+	indent := HorizontalSpacer(padding + theme.Sizes.MessageAvatarColumnWidth + theme.Sizes.MessageContentPadding)
+
+	main := container.NewBorder(nil, nil, avatarColumn, nil,
+		container.NewBorder(nil, nil, HorizontalSpacer(theme.Sizes.MessageContentPadding), nil, contentWidget))
+	row := container.NewBorder(nil, nil, HorizontalSpacer(padding), HorizontalSpacer(padding), main)
+
+	// A translucent cover dims the message until the server confirms it
+	dimmed := container.NewStack(row, canvas.NewRectangle(theme.Colors.PendingMessageDim))
+
+	lines := container.NewVBox()
+	for _, name := range w.Info.Attachments {
+		lines.Add(profileText(fmt.Sprintf("📎 %s", name), theme.Colors.PendingMessageText))
+	}
+
+	w.progress = widget.NewProgressBar()
+	w.progress.Hide()
+	lines.Add(w.progress)
+
+	w.status = profileText("Sending…", theme.Colors.PendingMessageText)
+	footer := container.NewHBox(w.status)
+	switch {
+	case w.Info.Sent:
+		footer.Hide()
+	case w.Info.Failed:
+		w.status.Text = "Failed to send"
+		if w.Info.Error != "" {
+			w.status.Text = fmt.Sprintf("Failed to send: %s", w.Info.Error)
+		}
+		w.status.Color = theme.Colors.PendingMessageFailed
+
+		retry := widget.NewButton("Retry", func() { w.call(w.OnRetry) })
+		retry.Importance = widget.LowImportance
+		discard := widget.NewButton("Discard", func() { w.call(w.OnDiscard) })
+		discard.Importance = widget.DangerImportance
+		footer.Add(layout.NewSpacer())
+		footer.Add(retry)
+		footer.Add(discard)
+	}
+	lines.Add(footer)
+
+	status := container.NewBorder(nil, nil, indent, HorizontalSpacer(padding), lines)
+
+	if len(message.Replies) == 0 {
+		return NewVerticalNoSpacingContainer(dimmed, status)
+	}
+	replies := profileText(fmt.Sprintf("↪ Replying to %d message(s)", len(message.Replies)), theme.Colors.TimestampText)
+	return NewVerticalNoSpacingContainer(
+		container.NewBorder(nil, nil, indent, nil, replies),
+		dimmed,
+		status,
+	)
+}
+
+// SetProgress shows how much of the message's attachments is uploaded, from 0 to 1.
+func (w *PendingMessage) SetProgress(fraction float64) {
+	w.status.Text = "Uploading…"
+	w.status.Refresh()
+	w.progress.SetValue(fraction)
+	w.progress.Show()
+}
+
+// CreateRenderer returns the widget renderer.
+func (w *PendingMessage) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(w.content)
+}
+
+// call invokes an optional callback with the message nonce.
+func (w *PendingMessage) call(action func(string)) {
+	if action != nil {
+		action(w.Info.Message.Nonce)
+	}
+}
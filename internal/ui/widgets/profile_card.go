@@ -0,0 +1,305 @@
+package widgets
+
+import (
+	"image/color"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	fyneTheme "fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/sentinelb51/revoltgo"
+
+	"RGOClient/internal/cache"
+	"RGOClient/internal/ui/markdown"
+	"RGOClient/internal/ui/theme"
+	"RGOClient/internal/util"
+)
+
+// Compile-time interface assertions.
+var _ fyne.Widget = (*ProfileCard)(nil)
+
+// ProfileRole is a server role shown on a profile card.
+type ProfileRole struct {
+	Name   string
+	Colour color.Color // nil for roles without a (supported) colour
+}
+
+// ProfileInfo is everything a profile card displays.
+// The card does no fetching; the caller fills this in and calls SetInfo as data arrives.
+type ProfileInfo struct {
+	UserID        string
+	AvatarURL     string
+	BannerURL     string
+	DisplayName   string
+	Username      string // username#discriminator
+	Presence      revoltgo.UserStatusPresence
+	Online        bool
+	StatusText    string
+	Bio           string
+	Badges        []string
+	Roles         []ProfileRole
+	MutualServers []string // Server names
+	Relationship  revoltgo.UserRelationshipType
+	IsSelf        bool
+	Loading       bool // Bio, banner and mutual servers are still being fetched
+}
+
+// ProfileCard shows a user's profile: banner, avatar, names, presence, bio, badges, roles and mutual servers.
+type ProfileCard struct {
+	widget.BaseWidget
+	OnMessage   func()
+	OnAddFriend func()
+
+	info ProfileInfo
+	body *fyne.Container
+}
+
+// NewProfileCard creates a profile card.
+func NewProfileCard(info ProfileInfo, onMessage, onAddFriend func()) *ProfileCard {
+	w := &ProfileCard{
+		OnMessage:   onMessage,
+		OnAddFriend: onAddFriend,
+		body:        container.NewVBox(),
+	}
+	w.ExtendBaseWidget(w)
+	w.SetInfo(info)
+	return w
+}
+
+// CreateRenderer returns the widget renderer.
+func (w *ProfileCard) CreateRenderer() fyne.WidgetRenderer {
+	bg := canvas.NewRectangle(theme.Colors.ProfileCardBg)
+	bg.CornerRadius = 8
+
+	scroll := container.NewVScroll(w.body)
+	return widget.NewSimpleRenderer(container.NewStack(bg, scroll))
+}
+
+// MinSize returns the fixed card size.
+func (w *ProfileCard) MinSize() fyne.Size {
+	return fyne.NewSize(theme.Sizes.ProfileCardWidth, theme.Sizes.ProfileCardHeight)
+}
+
+// SetInfo replaces the displayed profile.
+func (w *ProfileCard) SetInfo(info ProfileInfo) {
+	w.info = info
+	w.body.Objects = nil
+
+	w.body.Add(w.buildBanner())
+	w.body.Add(container.NewPadded(w.buildHeader()))
+
+	details := container.NewVBox()
+	if !info.IsSelf {
+		details.Add(w.buildButtons())
+	}
+
+	if info.Bio != "" {
+		bio := widget.NewRichText(markdown.NewRenderer(nil, nil).Render(info.Bio)...)
+		bio.Wrapping = fyne.TextWrapWord
+		details.Add(profileSection("About me", bio))
+	} else if info.Loading {
+		details.Add(profileSection("About me", profileText("Loading profile…", theme.Colors.TimestampText)))
+	}
+
+	if len(info.Badges) > 0 {
+		chips := make([]fyne.CanvasObject, len(info.Badges))
+		for i, badge := range info.Badges {
+			chips[i] = profileChip(badge, nil)
+		}
+		details.Add(profileSection("Badges", wrapRows(chips)))
+	}
+
+	if len(info.Roles) > 0 {
+		chips := make([]fyne.CanvasObject, len(info.Roles))
+		for i, role := range info.Roles {
+			chips[i] = profileChip(role.Name, role.Colour)
+		}
+		details.Add(profileSection("Roles", wrapRows(chips)))
+	}
+
+	if len(info.MutualServers) > 0 {
+		servers := widget.NewLabel(strings.Join(info.MutualServers, ", "))
+		servers.Wrapping = fyne.TextWrapWord
+		details.Add(profileSection("Mutual servers", servers))
+	}
+
+	w.body.Add(container.NewPadded(details))
+	w.body.Refresh()
+}
+
+// buildBanner returns the profile banner, or a flat colour if the user has none.
+func (w *ProfileCard) buildBanner() fyne.CanvasObject {
+	size := fyne.NewSize(theme.Sizes.ProfileCardWidth, theme.Sizes.ProfileBannerHeight)
+
+	placeholder := canvas.NewRectangle(theme.Colors.ProfileBannerBg)
+	placeholder.SetMinSize(size)
+	banner := container.NewGridWrap(size, placeholder)
+
+	if url := w.info.BannerURL; url != "" {
+		cache.GetImageCache().LoadImageToContainer(util.IDFromAttachmentURL(url), url, size, banner, false, nil)
+	}
+	return banner
+}
+
+// buildHeader returns the avatar, names and presence.
+func (w *ProfileCard) buildHeader() fyne.CanvasObject {
+	avatarSize := fyne.NewSquareSize(theme.Sizes.ProfileAvatarSize)
+	avatar := container.NewGridWrap(avatarSize, canvas.NewCircle(theme.Colors.AvatarPlaceholder))
+
+	if url := w.info.AvatarURL; url != "" {
+		avatarID := util.IDFromAttachmentURL(url)
+		if avatarID == "" {
+			avatarID = url
+		}
+		cache.GetImageCache().LoadImageToContainer(avatarID, url, avatarSize, avatar, true, nil)
+	}
+
+	displayName := profileText(w.info.DisplayName, theme.Colors.TextPrimary)
+	displayName.TextSize = 18
+	displayName.TextStyle = fyne.TextStyle{Bold: true}
+
+	names := container.NewVBox(displayName, profileText(w.info.Username, theme.Colors.TimestampText))
+
+	presenceColour, presenceText := presenceStyle(w.info.Presence, w.info.Online)
+	dot := canvas.NewCircle(presenceColour)
+	presence := HBoxNoSpacing(
+		container.NewCenter(container.NewGridWrap(fyne.NewSquareSize(theme.Sizes.PresenceDotSize), dot)),
+		HorizontalSpacer(6),
+		profileText(presenceText, theme.Colors.TimestampText),
+	)
+	names.Add(presence)
+
+	if w.info.StatusText != "" {
+		status := widget.NewLabel(w.info.StatusText)
+		status.Wrapping = fyne.TextWrapWord
+		names.Add(status)
+	}
+
+	return container.NewBorder(nil, nil, container.NewVBox(avatar), nil, container.NewPadded(names))
+}
+
+// buildButtons returns the "Message" and friend request buttons.
+func (w *ProfileCard) buildButtons() fyne.CanvasObject {
+	message := widget.NewButton("Message", func() {
+		if w.OnMessage != nil {
+			w.OnMessage()
+		}
+	})
+	message.Importance = widget.HighImportance
+
+	friendLabel := "Add friend"
+	switch w.info.Relationship {
+	case revoltgo.UserRelationsTypeFriend:
+		friendLabel = "Friends"
+	case revoltgo.UserRelationsTypeOutgoing:
+		friendLabel = "Request sent"
+	case revoltgo.UserRelationsTypeIncoming:
+		friendLabel = "Accept request"
+	}
+
+	friend := widget.NewButton(friendLabel, func() {
+		if w.OnAddFriend != nil {
+			w.OnAddFriend()
+		}
+	})
+
+	switch w.info.Relationship {
+	case revoltgo.UserRelationsTypeFriend, revoltgo.UserRelationsTypeOutgoing,
+		revoltgo.UserRelationsTypeBlocked, revoltgo.UserRelationsTypeBlockedOther:
+		friend.Disable()
+	}
+
+	return container.NewGridWithColumns(2, message, friend)
+}
+
+// presenceStyle returns the indicator colour and label for a presence.
+func presenceStyle(presence revoltgo.UserStatusPresence, online bool) (color.Color, string) {
+	if !online {
+		return theme.Colors.PresenceOffline, "Offline"
+	}
+
+	switch presence {
+	case revoltgo.UserStatusPresenceIdle:
+		return theme.Colors.PresenceIdle, "Idle"
+	case revoltgo.UserStatusPresenceFocus:
+		return theme.Colors.PresenceFocus, "Focus"
+	case revoltgo.UserStatusPresenceBusy:
+		return theme.Colors.PresenceBusy, "Do not disturb"
+	case revoltgo.UserStatusPresenceInvisible:
+		return theme.Colors.PresenceOffline, "Invisible"
+	default:
+		return theme.Colors.PresenceOnline, "Online"
+	}
+}
+
+// profileSection returns a titled block of the profile card.
+func profileSection(title string, content fyne.CanvasObject) fyne.CanvasObject {
+	label := profileText(strings.ToUpper(title), theme.Colors.CategoryText)
+	label.TextSize = 11
+	label.TextStyle = fyne.TextStyle{Bold: true}
+
+	return container.NewVBox(VerticalSpacer(4), label, content)
+}
+
+// profileChip returns a rounded label, with a coloured dot for roles.
+func profileChip(text string, dotColour color.Color) fyne.CanvasObject {
+	bg := canvas.NewRectangle(theme.Colors.ProfileChipBg)
+	bg.CornerRadius = 4
+
+	row := HBoxNoSpacing(HorizontalSpacer(6))
+	if dotColour != nil {
+		dot := container.NewGridWrap(fyne.NewSquareSize(theme.Sizes.PresenceDotSize), canvas.NewCircle(dotColour))
+		row.Add(container.NewCenter(dot))
+		row.Add(HorizontalSpacer(4))
+	}
+	row.Add(container.NewCenter(profileText(text, theme.Colors.TextPrimary)))
+	row.Add(HorizontalSpacer(6))
+
+	return container.NewStack(bg, container.NewBorder(VerticalSpacer(3), VerticalSpacer(3), nil, nil, row))
+}
+
+// profileText returns 12pt text in the given colour.
+func profileText(text string, c color.Color) *canvas.Text {
+	t := canvas.NewText(text, c)
+	t.TextSize = 12
+	return t
+}
+
+// wrapRows packs objects into rows that fit the card width.
+// Done up front because row-wrapping layouts only know their height after the first layout pass.
+func wrapRows(objects []fyne.CanvasObject) fyne.CanvasObject {
+	const gap = 4
+	maxWidth := theme.Sizes.ProfileCardWidth - 4*fyneTheme.Padding() // Two padded containers
+
+	rows := container.NewVBox()
+	row := HBoxNoSpacing()
+	var width float32
+	for _, obj := range objects {
+		objWidth := obj.MinSize().Width
+		if width > 0 && width+gap+objWidth > maxWidth {
+			rows.Add(row)
+			row, width = HBoxNoSpacing(), 0
+		}
+		if width > 0 {
+			row.Add(HorizontalSpacer(gap))
+			width += gap
+		}
+		row.Add(obj)
+		width += objWidth
+	}
+	rows.Add(row)
+	return rows
+}
+
+// ShowProfileCard opens a profile card in the centre of the canvas.
+func ShowProfileCard(c fyne.Canvas, card *ProfileCard) *widget.PopUp {
+	popup := widget.NewPopUp(card, c)
+	size := card.MinSize()
+	popup.ShowAtPosition(fyne.NewPos(
+		max(0, (c.Size().Width-size.Width)/2),
+		max(0, (c.Size().Height-size.Height)/2),
+	))
+	return popup
+}
@@ -0,0 +1,142 @@
+package widgets
+
+import (
+	"slices"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+	"github.com/sentinelb51/revoltgo"
+
+	"RGOClient/internal/context"
+	"RGOClient/internal/interfaces"
+	"RGOClient/internal/ui/theme"
+)
+
+// Compile-time interface assertions.
+var (
+	_ fyne.Widget       = (*reactionChip)(nil)
+	_ fyne.Tappable     = (*reactionChip)(nil)
+	_ desktop.Hoverable = (*reactionChip)(nil)
+)
+
+// reactionChip displays one emoji with its reaction count.
+// Chips we reacted with are highlighted; tapping toggles our reaction.
+type reactionChip struct {
+	widget.BaseWidget
+	content  fyne.CanvasObject
+	bg       *canvas.Rectangle
+	selected bool
+	onTap    func()
+}
+
+func newReactionChip(emojiID string, count int, selected bool, onTap func()) *reactionChip {
+	bg := canvas.NewRectangle(theme.Colors.ReactionBg)
+	bg.CornerRadius = theme.Sizes.ReactionHeight / 2
+	bg.SetMinSize(fyne.NewSize(0, theme.Sizes.ReactionHeight))
+	if selected {
+		bg.FillColor = theme.Colors.ReactionSelectedBg
+		bg.StrokeColor = theme.Colors.ReactionSelected
+		bg.StrokeWidth = 1
+	}
+
+	countText := canvas.NewText(strconv.Itoa(count), theme.Colors.SwiftActionText)
+	countText.TextSize = theme.Sizes.ReactionCountSize
+
+	row := HBoxNoSpacing(
+		HorizontalSpacer(8),
+		container.NewCenter(NewEmoji(emojiID, theme.Sizes.ReactionEmojiSize)),
+		HorizontalSpacer(6),
+		container.NewCenter(countText),
+		HorizontalSpacer(8),
+	)
+
+	w := &reactionChip{
+		content:  container.NewStack(bg, row),
+		bg:       bg,
+		selected: selected,
+		onTap:    onTap,
+	}
+	w.ExtendBaseWidget(w)
+	return w
+}
+
+// CreateRenderer returns the renderer for this widget.
+func (w *reactionChip) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(w.content)
+}
+
+// Tapped toggles the reaction.
+func (w *reactionChip) Tapped(*fyne.PointEvent) {
+	if w.onTap != nil {
+		w.onTap()
+	}
+}
+
+// MouseIn highlights the chip.
+func (w *reactionChip) MouseIn(*desktop.MouseEvent) {
+	if !w.selected {
+		w.bg.FillColor = theme.Colors.ReactionHoverBg
+		w.bg.Refresh()
+	}
+}
+
+// MouseMoved handles mouse movement within the chip.
+func (w *reactionChip) MouseMoved(*desktop.MouseEvent) {}
+
+// MouseOut restores the chip background.
+func (w *reactionChip) MouseOut() {
+	if !w.selected {
+		w.bg.FillColor = theme.Colors.ReactionBg
+		w.bg.Refresh()
+	}
+}
+
+// buildReactionsRow creates the row of reaction chips under a message.
+// Returns nil if the message has no reactions and no predefined set.
+func buildReactionsRow(message *revoltgo.Message, actions interfaces.MessageActions) fyne.CanvasObject {
+	emojiIDs := reactionOrder(message)
+	if len(emojiIDs) == 0 {
+		return nil
+	}
+
+	var selfID string
+	if session := context.Session(); session != nil {
+		if self := session.State.Self(); self != nil {
+			selfID = self.ID
+		}
+	}
+
+	row := container.NewHBox()
+	for _, emojiID := range emojiIDs {
+		users := message.Reactions[emojiID]
+		row.Add(newReactionChip(emojiID, len(users), slices.Contains(users, selfID), func() {
+			if actions != nil {
+				actions.OnReact(message, emojiID)
+			}
+		}))
+	}
+
+	return container.NewBorder(nil, nil, HorizontalSpacer(theme.Sizes.MessageTextLeftPadding), nil, row)
+}
+
+// reactionOrder returns the emojis to display: the predefined set first, then the rest sorted by ID.
+func reactionOrder(message *revoltgo.Message) []string {
+	var emojiIDs []string
+	if message.Interactions != nil {
+		emojiIDs = append(emojiIDs, message.Interactions.Reactions...)
+	}
+
+	var others []string
+	for emojiID, users := range message.Reactions {
+		if len(users) > 0 && !slices.Contains(emojiIDs, emojiID) {
+			others = append(others, emojiID)
+		}
+	}
+	slices.Sort(others)
+
+	return append(emojiIDs, others...)
+}
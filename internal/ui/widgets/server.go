@@ -24,6 +24,7 @@ type ServerWidget struct {
 	widget.BaseWidget
 	Server        *revoltgo.Server
 	onTap         func()
+	iconPath      string // Static icon instead of the server icon (Home)
 	background    *canvas.Circle
 	iconContainer *fyne.Container
 	iconWrapper   *fyne.Container
@@ -49,6 +50,14 @@ func NewServerWidget(server *revoltgo.Server, onTap func()) *ServerWidget {
 	return w
 }
 
+// NewHomeWidget creates the "Home" entry at the top of the server list.
+// It has an empty server ID, matching CurrentServerID while direct messages are shown.
+func NewHomeWidget(onTap func()) *ServerWidget {
+	w := NewServerWidget(&revoltgo.Server{Name: "Home"}, onTap)
+	w.iconPath = "assets/home.svg"
+	return w
+}
+
 // SetSelected updates the selection state and refreshes appearance.
 func (w *ServerWidget) SetSelected(selected bool) {
 	w.selected = selected
@@ -96,7 +105,12 @@ func (w *ServerWidget) CreateRenderer() fyne.WidgetRenderer {
 
 	w.iconContainer = container.NewStack(w.background, container.NewCenter(initialLabel))
 
-	if w.Server.Icon != nil {
+	if w.iconPath != "" {
+		icon := canvas.NewImageFromFile(w.iconPath)
+		icon.FillMode = canvas.ImageFillContain
+		icon.SetMinSize(fyne.NewSquareSize(w.baseSize / 2))
+		w.iconContainer.Objects = []fyne.CanvasObject{w.background, container.NewCenter(icon)}
+	} else if w.Server.Icon != nil {
 		cache.GetImageCache().LoadImageToContainer(w.Server.Icon.ID, w.Server.Icon.URL("64"), iconSize, w.iconContainer, true, w.background)
 	}
 
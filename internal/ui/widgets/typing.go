@@ -0,0 +1,67 @@
+package widgets
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"RGOClient/internal/ui/theme"
+)
+
+// maxTypingNames is how many names are listed before collapsing to "Several people".
+const maxTypingNames = 3
+
+// Compile-time interface assertion.
+var _ fyne.Widget = (*TypingIndicator)(nil)
+
+// TypingIndicator shows who is currently typing in the selected channel.
+// It keeps its height while empty so the message box does not jump.
+type TypingIndicator struct {
+	widget.BaseWidget
+	label *canvas.Text
+}
+
+// NewTypingIndicator creates an empty typing indicator.
+func NewTypingIndicator() *TypingIndicator {
+	w := &TypingIndicator{
+		label: canvas.NewText("", theme.Colors.TimestampText),
+	}
+	w.label.TextSize = theme.Sizes.TypingIndicatorTextSize
+	w.ExtendBaseWidget(w)
+	return w
+}
+
+// SetNames updates the list of users that are typing.
+func (w *TypingIndicator) SetNames(names []string) {
+	w.label.Text = FormatTyping(names)
+	w.label.Refresh()
+}
+
+// MinSize reserves a single line of text.
+func (w *TypingIndicator) MinSize() fyne.Size {
+	return fyne.NewSize(0, theme.Sizes.TypingIndicatorHeight)
+}
+
+// CreateRenderer returns the renderer for the typing indicator.
+func (w *TypingIndicator) CreateRenderer() fyne.WidgetRenderer {
+	content := container.NewBorder(nil, nil, HorizontalSpacer(theme.Sizes.MessageHorizontalPadding), nil, w.label)
+	return widget.NewSimpleRenderer(content)
+}
+
+// FormatTyping builds the "Alice and Bob are typing…" text for a list of names.
+func FormatTyping(names []string) string {
+	switch n := len(names); {
+	case n == 0:
+		return ""
+	case n == 1:
+		return fmt.Sprintf("%s is typing…", names[0])
+	case n > maxTypingNames:
+		return "Several people are typing…"
+	default:
+		return fmt.Sprintf("%s and %s are typing…", strings.Join(names[:n-1], ", "), names[n-1])
+	}
+}
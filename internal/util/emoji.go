@@ -0,0 +1,26 @@
+package util
+
+import (
+	"slices"
+
+	"github.com/sentinelb51/revoltgo"
+)
+
+// IsCustomEmoji returns true if the emoji ID refers to an uploaded emoji rather than a unicode one.
+func IsCustomEmoji(emojiID string) bool {
+	return IsULID(emojiID)
+}
+
+// EmojiURL returns the CDN URL for a custom emoji.
+func EmojiURL(emojiID string) string {
+	return revoltgo.EndpointAutumnFile("emojis", emojiID, "")
+}
+
+// CanReact reports whether the emoji may be used to react to the message.
+// Messages with restricted reactions only accept their predefined set.
+func CanReact(message *revoltgo.Message, emojiID string) bool {
+	if message.Interactions == nil || !message.Interactions.RestrictReactions {
+		return true
+	}
+	return slices.Contains(message.Interactions.Reactions, emojiID)
+}
@@ -18,6 +18,12 @@ func Timestamp(id string) (time.Time, error) {
 	return value.Timestamp(), nil
 }
 
+// IsULID reports whether id is a well-formed ULID, the format of all Revolt object IDs.
+func IsULID(id string) bool {
+	_, err := ulid.ParseStrict(id)
+	return err == nil
+}
+
 const (
 	timeLayout  = "3:04 PM"
 	daysInMonth = 30
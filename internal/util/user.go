@@ -0,0 +1,68 @@
+package util
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// badgeNames maps Revolt user badge bits to their names, in display order.
+var badgeNames = []struct {
+	bit  uint32
+	name string
+}{
+	{1 << 0, "Developer"},
+	{1 << 1, "Translator"},
+	{1 << 2, "Supporter"},
+	{1 << 3, "Responsible Disclosure"},
+	{1 << 4, "Founder"},
+	{1 << 5, "Platform Moderation"},
+	{1 << 6, "Active Supporter"},
+	{1 << 7, "Paw"},
+	{1 << 8, "Early Adopter"},
+	{1 << 9, "Joke Badge"},
+	{1 << 10, "Joke Badge"},
+}
+
+// BadgeNames returns the names of the badges set in a user's badge bitfield.
+func BadgeNames(badges uint32) []string {
+	var names []string
+	for _, badge := range badgeNames {
+		if badges&badge.bit != 0 {
+			names = append(names, badge.name)
+		}
+	}
+	return names
+}
+
+// FullUsername returns "username#discriminator", or just the username if there is no discriminator.
+func FullUsername(username, discriminator string) string {
+	if discriminator == "" {
+		return username
+	}
+	return fmt.Sprintf("%s#%s", username, discriminator)
+}
+
+// ParseHexColour parses a CSS hex colour ("#rgb" or "#rrggbb").
+// Role colours may also be CSS gradients or names, which are not supported.
+func ParseHexColour(value string) (color.Color, bool) {
+	hex, found := strings.CutPrefix(strings.TrimSpace(value), "#")
+	if !found {
+		return nil, false
+	}
+
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return nil, false
+	}
+
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, false
+	}
+
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 255}, true
+}